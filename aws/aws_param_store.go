@@ -2,24 +2,88 @@ package aws
 
 import (
 	"context"
+	"errors"
+	"io/fs"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
 	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/tree"
 	"github.com/railsware/go-global/v2/utils"
 )
 
 type LoadConfigOptions struct {
+	// ParamPrefix is the path of the params, e.g. "/myapp/production/".
+	// Leading and trailing slashes are optional. Required unless ParamPrefixes are set; "/" loads all params.
 	ParamPrefix string
+	// ParamPrefixes are additional prefixes, loaded concurrently and merged in order after ParamPrefix:
+	// params of a later prefix override those of an earlier one.
+	ParamPrefixes []PrefixMount
+	// Client is used to access Parameter Store. If not set, a client is created from the AWS config.
+	Client SSMClient
 	// If IgnoreUnmappedParams is set, a parameter with no matching config field will be silently ignored.
 	IgnoreUnmappedParams bool
+	// If RequireAllParams is set, every config field must be supplied by a parameter or a default.
+	// Otherwise, only fields tagged `global:",required"` must be.
+	RequireAllParams bool
+	// SnapshotCache, if set, saves the params after every successful load,
+	// and is used instead of Parameter Store if loading fails, with a warning.
+	SnapshotCache *SnapshotCache
+	// If FetchByName is set, only the params of the config fields are fetched, by names derived from the fields
+	// like they are matched when writing the config, see tree.ConfigParams. This needs access to those names only,
+	// rather than to the whole prefix, but the config must not have maps or slices of structs.
+	// Params that are not found are returned as warnings, see ParamNameError.
+	FetchByName bool
+	// Label pins params to their versions with the label in Parameter Store, e.g. "stable",
+	// or to a version number, e.g. "3". A field can be pinned with the `label:` tag instead.
+	// Labelled params are fetched by name, so setting Label enables FetchByName,
+	// and `label:` tags need FetchByName or Label, otherwise loading fails with tree.ErrInvalidTag.
+	Label string
+	// MissingLabel decides what happens if a param exists, but has no version with its label.
+	// By default, loading fails.
+	MissingLabel LabelPolicy
+	// Retry configures retries of failed requests, e.g. when Parameter Store is throttling.
+	// A retry resumes loading from the page that failed.
+	Retry RetryPolicy
+	// Provenance, if not nil, collects the origins of the loaded values, see tree.WriteOptions.
+	Provenance global.Provenance
+}
+
+// Reports whether params are fetched by the names of config fields, rather than all params under the prefixes.
+func (options LoadConfigOptions) fetchesByName() bool {
+	return options.FetchByName || options.Label != ""
+}
+
+func (options LoadConfigOptions) writeOptions() tree.WriteOptions {
+	return tree.WriteOptions{
+		RequireAll:           options.RequireAllParams,
+		ParamName:            options.paramName,
+		Provenance:           options.Provenance,
+		IgnoreUnmappedParams: options.IgnoreUnmappedParams,
+	}
 }
 
 // LoadConfigFromParameterStore retrieves keys configured in ParamStore and writes to config.
 //   - config must be a pointer to a struct.
 //   - Keys in ParamStore must be separated with slashes.
 //     They are matched to struct fields by: name, `global:` tag, or `json:` tag
-func LoadConfigFromParameterStore( //nolint:nonamedreturns // false positive, using named return for defer
+func LoadConfigFromParameterStore(
+	awsConfig aws.Config,
+	options LoadConfigOptions,
+	globalConfig interface{},
+) global.Error {
+	return LoadConfigFromParameterStoreWithContext(context.Background(), awsConfig, options, globalConfig)
+}
+
+// LoadConfigFromParameterStoreWithContext is LoadConfigFromParameterStore that stops loading when ctx is done.
+// The error of cancelled loading unwraps to ctx.Err(), i.e. context.Canceled or context.DeadlineExceeded.
+func LoadConfigFromParameterStoreWithContext( //nolint:nonamedreturns // false positive, using named return for defer
+	ctx context.Context,
 	awsConfig aws.Config,
 	options LoadConfigOptions,
 	globalConfig interface{},
@@ -31,47 +95,240 @@ func LoadConfigFromParameterStore( //nolint:nonamedreturns // false positive, us
 		}
 	}()
 
-	reflectedConfig, err := utils.ReflectConfig(globalConfig)
-	if err != nil {
+	if _, err := utils.ReflectConfig(globalConfig); err != nil {
 		return err
 	}
 
-	paramPaginator := ssm.NewGetParametersByPathPaginator(
-		ssm.NewFromConfig(awsConfig),
-		&ssm.GetParametersByPathInput{
-			Path:           aws.String(options.ParamPrefix),
-			Recursive:      aws.Bool(true),
-			WithDecryption: aws.Bool(true),
-		},
-	)
+	paramTree, err := loadParamTree(ctx, awsConfig, options, globalConfig)
+	if err != nil && !err.Warning() {
+		return err
+	}
+
+	return global.JoinErrors(err, paramTree.WriteConfig(globalConfig, options.writeOptions()))
+}
+
+// ParameterStoreSource returns a source of the parameter tree stored under the options' prefixes,
+// to be combined with other sources. options.IgnoreUnmappedParams has no effect here.
+// If the tree comes from options.SnapshotCache, it is returned with a warning.
+// options.FetchByName is not supported, as the source doesn't know the config.
+func ParameterStoreSource(awsConfig aws.Config, options LoadConfigOptions) tree.Source {
+	return func() (*tree.Node, global.Error) {
+		return loadParamTree(context.Background(), awsConfig, options, nil)
+	}
+}
+
+// Loads the tree of params for the config, which is only used with options.FetchByName.
+func loadParamTree(
+	ctx context.Context,
+	awsConfig aws.Config,
+	options LoadConfigOptions,
+	config interface{},
+) (*tree.Node, global.Error) {
+	// a config error is not a reason to fall back to the snapshot
+	if config != nil && !options.fetchesByName() {
+		if err := checkNoLabelTags(config); err != nil {
+			return nil, err
+		}
+	}
+	mounts := options.prefixMounts()
+	for _, mount := range mounts {
+		if err := checkParamPrefix(mount.ParamPrefix); err != nil {
+			return nil, err
+		}
+	}
+
+	client := options.Client
+	if client == nil {
+		client = ssm.NewFromConfig(awsConfig)
+	}
+
+	paramsByMount, err := fetchConfigParams(ctx, client, mounts, options, config)
+
+	cache := options.SnapshotCache
+	if cache == nil {
+		if err != nil && !err.Warning() {
+			return nil, err
+		}
+		return buildMountedParamTree(paramsByMount), err
+	}
+
+	if err != nil && !err.Warning() {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		cachedParams, savedAt, cacheErr := cache.load(newSnapshotKey(mounts, options), time.Now())
+		if errors.Is(cacheErr, fs.ErrNotExist) {
+			return nil, err
+		}
+		if cacheErr != nil {
+			return nil, global.JoinErrors(err, global.WrapError(cacheErr, "global: cannot use snapshot: %v", cacheErr))
+		}
+		warning := global.WrapWarning(err, "global: using snapshot saved at %s: %v", savedAt.Format(time.RFC3339), err)
+		return buildMountedParamTree(cachedParams), warning
+	}
+
+	if saveErr := cache.save(newSnapshotKey(mounts, options), paramsByMount, time.Now()); saveErr != nil {
+		err = global.JoinErrors(err, global.WrapWarning(saveErr, "global: cannot save snapshot: %v", saveErr))
+	}
+	return buildMountedParamTree(paramsByMount), err
+}
+
+// Fetches params of all mounts, either all params under their prefixes or by the names of config fields.
+func fetchConfigParams(
+	ctx context.Context,
+	client SSMClient,
+	mounts []PrefixMount,
+	options LoadConfigOptions,
+	config interface{},
+) ([][]param, global.Error) {
+	if !options.fetchesByName() {
+		return fetchMountedParams(ctx, client, mounts, options.Retry)
+	}
 
-	var params []param
+	getClient, ok := client.(SSMGetClient)
+	if !ok {
+		return nil, global.NewError("global: fetching params by name needs a client with GetParameters")
+	}
+	if config == nil {
+		return nil, global.NewError("global: fetching params by name needs a config")
+	}
+	configParams, err := tree.ConfigParams(config)
+	if err != nil {
+		return nil, err
+	}
+	return fetchMountedParamsByName(ctx, getClient, mounts, configParams, options)
+}
 
-	for paramPaginator.HasMorePages() {
-		page, err := paramPaginator.NextPage(context.Background())
+// Fetches params of all mounts concurrently.
+func fetchMountedParams(
+	ctx context.Context,
+	client SSMClient,
+	mounts []PrefixMount,
+	retryPolicy RetryPolicy,
+) ([][]param, global.Error) {
+	paramsByMount := make([][]param, len(mounts))
+	errs := make([]global.Error, len(mounts))
+
+	var waitGroup sync.WaitGroup
+	for index, mount := range mounts {
+		waitGroup.Add(1)
+		go func(index int, mount PrefixMount) {
+			defer waitGroup.Done()
+			paramsByMount[index], errs[index] = fetchMountParams(ctx, client, mount, retryPolicy)
+		}(index, mount)
+	}
+	waitGroup.Wait()
+
+	for _, err := range errs {
 		if err != nil {
-			return global.NewError("global: failed to load from Parameter Store: %v", err)
+			return nil, err
 		}
-		for _, ssmParam := range page.Parameters {
-			paramNameWithoutPrefix := (*ssmParam.Name)[len(options.ParamPrefix):]
-			params = append(params, param{paramNameWithoutPrefix, *ssmParam.Value})
+	}
+	return paramsByMount, nil
+}
+
+// Merges trees of the params of each mount, in order.
+func buildMountedParamTree(paramsByMount [][]param) *tree.Node {
+	paramTree := new(tree.Node)
+	for _, params := range paramsByMount {
+		if len(params) > 0 {
+			paramTree = tree.Merge(paramTree, buildParamTree(params))
 		}
 	}
+	return paramTree
+}
+
+// Fetches params stored under the mount's prefix, with paths relative to the root of the config.
+func fetchMountParams(
+	ctx context.Context,
+	client SSMClient,
+	mount PrefixMount,
+	retryPolicy RetryPolicy,
+) ([]param, global.Error) {
+	paramPrefix := normalizeParamPrefix(mount.ParamPrefix)
+	mountPath := mountPathPrefix(mount.MountPath)
+
+	ssmParams, err := fetchParams(ctx, client, paramPrefix, retryPolicy)
+	if err != nil {
+		return nil, err
+	}
 
-	paramTree := buildParamTree(params)
+	params := make([]param, 0, len(ssmParams))
+	for _, ssmParam := range ssmParams {
+		paramNameWithoutPrefix := (*ssmParam.Name)[len(paramPrefix):]
+		params = append(params, newParam(ssmParam, mountPath+paramNameWithoutPrefix, paramPrefix))
+	}
 
-	errors := paramTree.Write(reflectedConfig)
+	return params, nil
+}
 
-	joinedError := errors.Join()
+// Converts the Parameter Store param stored under the normalised prefix to a param at the config path.
+func newParam(ssmParam types.Parameter, path string, paramPrefix string) param {
+	return param{
+		path:  path,
+		value: aws.ToString(ssmParam.Value),
+		origin: &global.Origin{
+			Backend:      global.BackendParameterStore,
+			Name:         aws.ToString(ssmParam.Name),
+			Prefix:       paramPrefix,
+			Type:         string(ssmParam.Type),
+			Version:      strconv.FormatInt(ssmParam.Version, 10),
+			LastModified: aws.ToTime(ssmParam.LastModifiedDate),
+		},
+	}
+}
 
-	if joinedError.Warning() && options.IgnoreUnmappedParams {
-		return nil
+// Fetches all params stored under the normalised prefix, decrypted.
+// A failed page is retried according to the policy, with the same token.
+func fetchParams(
+	ctx context.Context,
+	client SSMClient,
+	paramPrefix string,
+	retryPolicy RetryPolicy,
+) ([]types.Parameter, global.Error) {
+	input := &ssm.GetParametersByPathInput{
+		Path:           aws.String(paramPath(paramPrefix)),
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(true),
 	}
 
-	return joinedError
+	var ssmParams []types.Parameter
+
+	for {
+		page, err := retry(ctx, retryPolicy, func() (*ssm.GetParametersByPathOutput, error) {
+			return client.GetParametersByPath(ctx, input)
+		})
+		if err != nil {
+			return nil, wrapLoadError(ctx, err, "Parameter Store")
+		}
+		for _, ssmParam := range page.Parameters {
+			if !strings.HasPrefix(*ssmParam.Name, paramPrefix) {
+				return nil, global.NewError("global: parameter %s does not match prefix %s", *ssmParam.Name, paramPrefix)
+			}
+			ssmParams = append(ssmParams, ssmParam)
+		}
+
+		// like ssm.GetParametersByPathPaginator, stop if the token doesn't change
+		if page.NextToken == nil || aws.ToString(page.NextToken) == "" ||
+			aws.ToString(page.NextToken) == aws.ToString(input.NextToken) {
+			return ssmParams, nil
+		}
+		nextInput := *input
+		nextInput.NextToken = page.NextToken
+		input = &nextInput
+	}
+}
+
+// Wraps an error of an AWS API call, so that it unwraps to ctx.Err() if loading was cancelled.
+func wrapLoadError(ctx context.Context, err error, serviceName string) global.Error {
+	if ctx.Err() != nil {
+		return global.WrapError(ctx.Err(), "global: loading from %s was cancelled: %v", serviceName, err)
+	}
+	return global.WrapError(err, "global: failed to load from %s: %v", serviceName, err)
 }
 
 type param struct {
-	path  string
-	value string
+	path   string
+	value  string
+	origin *global.Origin
 }
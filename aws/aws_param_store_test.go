@@ -0,0 +1,202 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/aws/awstest"
+	"github.com/railsware/go-global/v2/tree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type paramStoreConfig struct {
+	Database struct {
+		PoolSize int      `json:"pool_size"`
+		URLs     []string `json:"urls"`
+	} `json:"database"`
+}
+
+func newFakeSSM() *awstest.SSM {
+	fake := awstest.NewSSM()
+	fake.PageSize = 2
+	fake.Put("/app/database/pool_size", "10")
+	fake.Put("/app/database/urls/0", "postgres://primary")
+	fake.Put("/app/database/urls/1", "postgres://replica")
+	fake.Put("/app/unmapped", "foo")
+	fake.Put("/other/database/pool_size", "20")
+	return fake
+}
+
+func TestLoadConfigFromParameterStore(t *testing.T) {
+	t.Parallel()
+
+	var config paramStoreConfig
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: newFakeSSM()},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.True(t, err.Warning())
+	assert.Equal(t, "global: unmapped: unknown field (from /app/unmapped)", err.Error())
+
+	assert.Equal(t, 10, config.Database.PoolSize)
+	assert.Equal(t, []string{"postgres://primary", "postgres://replica"}, config.Database.URLs)
+
+	err = LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: newFakeSSM(), IgnoreUnmappedParams: true},
+		&config,
+	)
+	assert.Nil(t, err)
+}
+
+func TestLoadConfigFromParameterStoreCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var config paramStoreConfig
+	err := LoadConfigFromParameterStoreWithContext(
+		ctx,
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: newFakeSSM()},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Contains(t, err.Error(), "cancelled")
+}
+
+func TestLoadConfigFromParameterStoreReportsMissingParams(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Database struct {
+			PoolSize int    `json:"pool_size"`
+			Password string `json:"password" global:",required"`
+		} `json:"database"`
+	}
+
+	var loadedConfig config
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: newFakeSSM(), IgnoreUnmappedParams: true},
+		&loadedConfig,
+	)
+	require.NotNil(t, err)
+	assert.False(t, err.Warning())
+	assert.ErrorIs(t, err, tree.ErrMissing)
+	assert.Contains(t, err.Error(), "database/password: missing parameter /app/database/password")
+}
+
+func TestLoadConfigFromMultipleParameterStorePrefixes(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Name     string `json:"name"`
+		Region   string `json:"region"`
+		Database struct {
+			PoolSize int    `json:"pool_size"`
+			Host     string `json:"host"`
+		} `json:"database"`
+	}
+
+	fake := newFakeSSM()
+	fake.Put("/shared/name", "shared")
+	fake.Put("/shared/region", "eu-west-1")
+	fake.Put("/billing/prod/name", "billing")
+	fake.Put("/databases/billing/host", "db.internal")
+
+	var loadedConfig config
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{
+			ParamPrefixes: []PrefixMount{
+				{ParamPrefix: "/shared/"},
+				{ParamPrefix: "/billing/prod/"},
+				{ParamPrefix: "/databases/billing", MountPath: "/database/"},
+			},
+			Client: fake,
+		},
+		&loadedConfig,
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "billing", loadedConfig.Name)
+	assert.Equal(t, "eu-west-1", loadedConfig.Region)
+	assert.Equal(t, "db.internal", loadedConfig.Database.Host)
+}
+
+func TestErrorsNameTheParameterStorePrefix(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeSSM()
+	fake.Put("/shared/database/pool_size", "many")
+
+	var config paramStoreConfig
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{
+			ParamPrefix:   "/app/",
+			ParamPrefixes: []PrefixMount{{ParamPrefix: "/shared/"}},
+			Client:        fake,
+		},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.ErrorIs(t, err, tree.ErrParse)
+	assert.Contains(t, err.Error(), "(from /shared/database/pool_size)")
+
+	var writeErrors tree.WriteErrors
+	require.ErrorAs(t, err, &writeErrors)
+	for _, writeError := range writeErrors.Errors() {
+		if writeError.Kind == tree.ErrParse {
+			assert.Equal(t, "/shared/", writeError.Origin.Prefix)
+		}
+	}
+}
+
+func TestLoadStringListFromParameterStore(t *testing.T) {
+	t.Parallel()
+
+	fake := awstest.NewSSM()
+	fake.PutWithType("/app/database/urls", "postgres://primary,postgres://replica", types.ParameterTypeStringList)
+
+	var config paramStoreConfig
+	err := LoadConfigFromParameterStore(aws.Config{}, LoadConfigOptions{ParamPrefix: "/app/", Client: fake}, &config)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"postgres://primary", "postgres://replica"}, config.Database.URLs)
+}
+
+func TestExplainParameterStoreValues(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeSSM()
+	fake.Put("/app/database/pool_size", "15")
+
+	var config paramStoreConfig
+	provenance := make(global.Provenance)
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: fake, IgnoreUnmappedParams: true, Provenance: provenance},
+		&config,
+	)
+	require.Nil(t, err)
+
+	origin, ok := provenance["Database.PoolSize"]
+	require.True(t, ok)
+	assert.Equal(t, global.BackendParameterStore, origin.Backend)
+	assert.Equal(t, "/app/database/pool_size", origin.Name)
+	assert.Equal(t, "/app/", origin.Prefix)
+	assert.Equal(t, "2", origin.Version)
+	assert.False(t, origin.LastModified.IsZero())
+
+	origin, ok = provenance["Database.URLs[1]"]
+	require.True(t, ok)
+	assert.Equal(t, "/app/database/urls/1", origin.Name)
+}
@@ -0,0 +1,171 @@
+package awstest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+const secretARNPrefix = "arn:aws:secretsmanager:us-east-1:123456789012:secret:"
+
+// SecretsManager is an in-memory Secrets Manager. It is safe for concurrent use.
+type SecretsManager struct {
+	// PageSize limits the number of secrets per page, unless the request asks for fewer.
+	// Defaults to 10.
+	PageSize int
+
+	mutex   sync.Mutex
+	secrets map[string]secret
+}
+
+type secret struct {
+	arn          string
+	value        string
+	version      int
+	lastModified time.Time
+}
+
+func NewSecretsManager() *SecretsManager {
+	return &SecretsManager{secrets: make(map[string]secret)}
+}
+
+// Put stores a secret string, creating a new version of the secret.
+func (s *SecretsManager) Put(name, value string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.secrets[name] = secret{
+		arn:          secretARNPrefix + name + "-AbCdEf",
+		value:        value,
+		version:      s.secrets[name].version + 1,
+		lastModified: time.Now(),
+	}
+}
+
+// Delete removes a secret.
+func (s *SecretsManager) Delete(name string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.secrets, name)
+}
+
+// ListSecrets implements aws.SecretsManagerClient.
+// Only the "name" filter is supported; like in the real API, it matches name prefixes case-insensitively.
+func (s *SecretsManager) ListSecrets(
+	ctx context.Context,
+	params *secretsmanager.ListSecretsInput,
+	_ ...func(*secretsmanager.Options),
+) (*secretsmanager.ListSecretsOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var namePrefixes []string
+	for _, filter := range params.Filters {
+		if filter.Key != types.FilterNameStringTypeName {
+			return nil, &types.InvalidParameterException{Message: aws.String(fmt.Sprintf("unsupported filter %s", filter.Key))}
+		}
+		for _, value := range filter.Values {
+			namePrefixes = append(namePrefixes, strings.ToLower(value))
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var names []string
+	for name := range s.secrets {
+		if matchesAnyPrefix(strings.ToLower(name), namePrefixes) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	start := 0
+	if params.NextToken != nil {
+		var err error
+		start, err = strconv.Atoi(*params.NextToken)
+		if err != nil || start < 0 || start > len(names) {
+			return nil, &types.InvalidNextTokenException{Message: aws.String("invalid next token")}
+		}
+	}
+
+	pageSize := s.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if params.MaxResults != nil && int(*params.MaxResults) < pageSize {
+		pageSize = int(*params.MaxResults)
+	}
+
+	end := start + pageSize
+	if end > len(names) {
+		end = len(names)
+	}
+
+	output := &secretsmanager.ListSecretsOutput{}
+	for _, name := range names[start:end] {
+		output.SecretList = append(output.SecretList, types.SecretListEntry{
+			ARN:              aws.String(s.secrets[name].arn),
+			Name:             aws.String(name),
+			LastChangedDate:  aws.Time(s.secrets[name].lastModified),
+			LastAccessedDate: aws.Time(s.secrets[name].lastModified),
+		})
+	}
+	if end < len(names) {
+		output.NextToken = aws.String(strconv.Itoa(end))
+	}
+	return output, nil
+}
+
+// GetSecretValue implements aws.SecretsManagerClient. SecretId may be either the name or the ARN of the secret.
+func (s *SecretsManager) GetSecretValue(
+	ctx context.Context,
+	params *secretsmanager.GetSecretValueInput,
+	_ ...func(*secretsmanager.Options),
+) (*secretsmanager.GetSecretValueOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	secretID := aws.ToString(params.SecretId)
+	for name, secret := range s.secrets {
+		if name != secretID && secret.arn != secretID {
+			continue
+		}
+		return &secretsmanager.GetSecretValueOutput{
+			ARN:           aws.String(secret.arn),
+			Name:          aws.String(name),
+			SecretString:  aws.String(secret.value),
+			VersionId:     aws.String(strconv.Itoa(secret.version)),
+			VersionStages: []string{"AWSCURRENT"},
+			CreatedDate:   aws.Time(secret.lastModified),
+		}, nil
+	}
+	return nil, &types.ResourceNotFoundException{
+		Message: aws.String(fmt.Sprintf("Secrets Manager can't find the specified secret %s", secretID)),
+	}
+}
+
+func matchesAnyPrefix(name string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package awstest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListSecrets(t *testing.T) {
+	t.Parallel()
+
+	fake := NewSecretsManager()
+	fake.PageSize = 2
+	fake.Put("app/a", "1")
+	fake.Put("App/b", "2")
+	fake.Put("app/c", "3")
+	fake.Put("other/a", "4")
+
+	ctx := context.Background()
+	input := &secretsmanager.ListSecretsInput{
+		Filters: []types.Filter{{Key: types.FilterNameStringTypeName, Values: []string{"app/"}}},
+	}
+
+	output, err := fake.ListSecrets(ctx, input)
+	require.NoError(t, err)
+	require.Len(t, output.SecretList, 2)
+	assert.Equal(t, "App/b", *output.SecretList[0].Name, "case-insensitive")
+	assert.Equal(t, "app/a", *output.SecretList[1].Name)
+	require.NotNil(t, output.NextToken)
+
+	input.NextToken = output.NextToken
+	output, err = fake.ListSecrets(ctx, input)
+	require.NoError(t, err)
+	require.Len(t, output.SecretList, 1)
+	assert.Equal(t, "app/c", *output.SecretList[0].Name)
+	assert.Nil(t, output.NextToken)
+}
+
+func TestGetSecretValue(t *testing.T) {
+	t.Parallel()
+
+	fake := NewSecretsManager()
+	fake.Put("app/a", "1")
+	fake.Put("app/a", "2")
+
+	ctx := context.Background()
+	output, err := fake.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String("app/a")})
+	require.NoError(t, err)
+	assert.Equal(t, "2", *output.SecretString)
+	assert.Equal(t, "2", *output.VersionId)
+
+	output, err = fake.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: output.ARN})
+	require.NoError(t, err)
+	assert.Equal(t, "app/a", *output.Name)
+
+	fake.Delete("app/a")
+	_, err = fake.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String("app/a")})
+	var notFound *types.ResourceNotFoundException
+	assert.ErrorAs(t, err, &notFound)
+}
@@ -0,0 +1,299 @@
+// Package awstest provides in-memory stand-ins for AWS APIs used by the aws package.
+package awstest
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/smithy-go"
+)
+
+const (
+	defaultPageSize       = 10
+	maxGetParametersNames = 10
+	pathSeparator         = "/"
+)
+
+// SSM is an in-memory Parameter Store. It is safe for concurrent use.
+type SSM struct {
+	// PageSize limits the number of parameters per page, unless the request asks for fewer.
+	// Defaults to 10, like the real API.
+	PageSize int
+
+	mutex      sync.Mutex
+	parameters map[string]types.Parameter
+	// all versions of each parameter, starting from version 1
+	history map[string][]types.Parameter
+	// version of each parameter by label
+	labels map[string]map[string]int64
+}
+
+func NewSSM() *SSM {
+	return &SSM{
+		parameters: make(map[string]types.Parameter),
+		history:    make(map[string][]types.Parameter),
+		labels:     make(map[string]map[string]int64),
+	}
+}
+
+// Put stores a String parameter.
+func (s *SSM) Put(name, value string) {
+	s.PutWithType(name, value, types.ParameterTypeString)
+}
+
+// PutWithType stores a parameter of the given type, bumping its version.
+func (s *SSM) PutWithType(name, value string, parameterType types.ParameterType) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.put(name, value, parameterType)
+}
+
+// Stores a parameter, mutex must be held.
+func (s *SSM) put(name, value string, parameterType types.ParameterType) {
+	version := s.parameters[name].Version + 1
+	s.parameters[name] = types.Parameter{
+		Name:             aws.String(name),
+		Value:            aws.String(value),
+		Type:             parameterType,
+		Version:          version,
+		LastModifiedDate: aws.Time(time.Now()),
+		DataType:         aws.String("text"),
+	}
+	s.history[name] = append(s.history[name], s.parameters[name])
+}
+
+// PutParameter implements aws.SSMPutClient. Like the real API, it fails if the parameter exists,
+// unless Overwrite is set.
+func (s *SSM) PutParameter(
+	ctx context.Context,
+	params *ssm.PutParameterInput,
+	_ ...func(*ssm.Options),
+) (*ssm.PutParameterOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	name := aws.ToString(params.Name)
+	if !strings.HasPrefix(name, pathSeparator) || aws.ToString(params.Value) == "" {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("invalid parameter %q", name)}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, exists := s.parameters[name]; exists && !aws.ToBool(params.Overwrite) {
+		return nil, &types.ParameterAlreadyExists{Message: aws.String(fmt.Sprintf("parameter %s already exists", name))}
+	}
+
+	parameterType := params.Type
+	if parameterType == "" {
+		parameterType = types.ParameterTypeString
+	}
+	s.put(name, aws.ToString(params.Value), parameterType)
+
+	return &ssm.PutParameterOutput{Version: s.parameters[name].Version, Tier: types.ParameterTierStandard}, nil
+}
+
+// Delete removes a parameter.
+func (s *SSM) Delete(name string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.parameters, name)
+	delete(s.history, name)
+	delete(s.labels, name)
+}
+
+// LabelParameterVersion implements the API to attach labels to a version of a parameter, the latest one by default.
+// Like the real API, a label is moved from the version it was attached to before,
+// and labels that are numbers or have the aws or ssm prefix are invalid.
+func (s *SSM) LabelParameterVersion(
+	ctx context.Context,
+	params *ssm.LabelParameterVersionInput,
+	_ ...func(*ssm.Options),
+) (*ssm.LabelParameterVersionOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	name := aws.ToString(params.Name)
+	latest, ok := s.parameters[name]
+	if !ok {
+		return nil, &types.ParameterNotFound{Message: aws.String(fmt.Sprintf("parameter %s not found", name))}
+	}
+	version := latest.Version
+	if params.ParameterVersion != nil {
+		version = *params.ParameterVersion
+	}
+	if version < 1 || version > latest.Version {
+		return nil, &types.ParameterVersionNotFound{Message: aws.String(fmt.Sprintf("version %d not found", version))}
+	}
+
+	if s.labels[name] == nil {
+		s.labels[name] = make(map[string]int64)
+	}
+	output := &ssm.LabelParameterVersionOutput{ParameterVersion: version}
+	for _, label := range params.Labels {
+		if !validLabel(label) {
+			output.InvalidLabels = append(output.InvalidLabels, label)
+			continue
+		}
+		s.labels[name][label] = version
+	}
+	return output, nil
+}
+
+func validLabel(label string) bool {
+	lowerLabel := strings.ToLower(label)
+	if label == "" || strings.HasPrefix(lowerLabel, "aws") || strings.HasPrefix(lowerLabel, "ssm") {
+		return false
+	}
+	_, err := strconv.ParseInt(label, 10, 64)
+	return err != nil
+}
+
+// GetParametersByPath implements aws.SSMClient.
+// SecureString values are returned encoded with base64 unless decryption is requested.
+func (s *SSM) GetParametersByPath(
+	ctx context.Context,
+	params *ssm.GetParametersByPathInput,
+	_ ...func(*ssm.Options),
+) (*ssm.GetParametersByPathOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	path := aws.ToString(params.Path)
+	if !strings.HasPrefix(path, pathSeparator) {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("invalid path %q", path)}
+	}
+	pathPrefix := strings.TrimSuffix(path, pathSeparator) + pathSeparator
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var names []string
+	for name := range s.parameters {
+		if !strings.HasPrefix(name, pathPrefix) {
+			continue
+		}
+		if !aws.ToBool(params.Recursive) && strings.Contains(name[len(pathPrefix):], pathSeparator) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	start := 0
+	if params.NextToken != nil {
+		var err error
+		start, err = strconv.Atoi(*params.NextToken)
+		if err != nil || start < 0 || start > len(names) {
+			return nil, &types.InvalidNextToken{Message: aws.String("invalid next token")}
+		}
+	}
+
+	pageSize := s.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if params.MaxResults != nil && int(*params.MaxResults) < pageSize {
+		pageSize = int(*params.MaxResults)
+	}
+
+	end := start + pageSize
+	if end > len(names) {
+		end = len(names)
+	}
+
+	output := &ssm.GetParametersByPathOutput{}
+	for _, name := range names[start:end] {
+		output.Parameters = append(output.Parameters, s.parameter(name, aws.ToBool(params.WithDecryption)))
+	}
+	if end < len(names) {
+		output.NextToken = aws.String(strconv.Itoa(end))
+	}
+	return output, nil
+}
+
+// GetParameters implements aws.SSMGetClient. Like the real API, it accepts up to 10 names,
+// optionally with a selector of a version or a label, e.g. "/app/a:3" or "/app/a:stable".
+// Names that are not found are listed as invalid.
+func (s *SSM) GetParameters(
+	ctx context.Context,
+	params *ssm.GetParametersInput,
+	_ ...func(*ssm.Options),
+) (*ssm.GetParametersOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(params.Names) == 0 || len(params.Names) > maxGetParametersNames {
+		return nil, &smithy.GenericAPIError{
+			Code:    "ValidationException",
+			Message: fmt.Sprintf("expected 1 to %d names, got %d", maxGetParametersNames, len(params.Names)),
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	output := &ssm.GetParametersOutput{}
+	for _, nameWithSelector := range params.Names {
+		parameter, ok := s.selectParameter(nameWithSelector)
+		if !ok {
+			output.InvalidParameters = append(output.InvalidParameters, nameWithSelector)
+			continue
+		}
+		output.Parameters = append(output.Parameters, decrypt(parameter, aws.ToBool(params.WithDecryption)))
+	}
+	return output, nil
+}
+
+// Returns the version of the parameter selected like "name:version" or "name:label", or the latest one.
+// The mutex must be held.
+func (s *SSM) selectParameter(nameWithSelector string) (types.Parameter, bool) {
+	name, selector, hasSelector := strings.Cut(nameWithSelector, ":")
+	if !hasSelector {
+		parameter, ok := s.parameters[name]
+		return parameter, ok
+	}
+
+	version, err := strconv.ParseInt(selector, 10, 64)
+	if err != nil {
+		var ok bool
+		if version, ok = s.labels[name][selector]; !ok {
+			return types.Parameter{}, false
+		}
+	}
+	history := s.history[name]
+	if version < 1 || version > int64(len(history)) {
+		return types.Parameter{}, false
+	}
+	parameter := history[version-1]
+	parameter.Selector = aws.String(":" + selector)
+	return parameter, true
+}
+
+// Returns a copy of the parameter, mutex must be held.
+func (s *SSM) parameter(name string, withDecryption bool) types.Parameter {
+	return decrypt(s.parameters[name], withDecryption)
+}
+
+// Returns the parameter with its value encoded with base64, as if encrypted, unless decryption is requested.
+func decrypt(parameter types.Parameter, withDecryption bool) types.Parameter {
+	if parameter.Type == types.ParameterTypeSecureString && !withDecryption {
+		parameter.Value = aws.String(base64.StdEncoding.EncodeToString([]byte(*parameter.Value)))
+	}
+	return parameter
+}
@@ -0,0 +1,173 @@
+package awstest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parameterNames(output *ssm.GetParametersByPathOutput) []string {
+	names := make([]string, 0, len(output.Parameters))
+	for _, parameter := range output.Parameters {
+		names = append(names, *parameter.Name)
+	}
+	return names
+}
+
+func TestGetParametersByPath(t *testing.T) {
+	t.Parallel()
+
+	fake := NewSSM()
+	fake.Put("/app/a", "1")
+	fake.Put("/app/b/c", "2")
+	fake.Put("/app/b/d", "3")
+	fake.Put("/other/a", "4")
+	fake.PutWithType("/app/secret", "password", types.ParameterTypeSecureString)
+
+	ctx := context.Background()
+
+	output, err := fake.GetParametersByPath(ctx, &ssm.GetParametersByPathInput{Path: aws.String("/app")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/app/a", "/app/secret"}, parameterNames(output), "not recursive")
+	assert.Equal(t, "cGFzc3dvcmQ=", *output.Parameters[1].Value, "not decrypted")
+
+	output, err = fake.GetParametersByPath(ctx, &ssm.GetParametersByPathInput{
+		Path:           aws.String("/app/"),
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(true),
+		MaxResults:     aws.Int32(3),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/app/a", "/app/b/c", "/app/b/d"}, parameterNames(output))
+	require.NotNil(t, output.NextToken)
+
+	output, err = fake.GetParametersByPath(ctx, &ssm.GetParametersByPathInput{
+		Path:           aws.String("/app/"),
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(true),
+		MaxResults:     aws.Int32(3),
+		NextToken:      output.NextToken,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/app/secret"}, parameterNames(output))
+	assert.Equal(t, "password", *output.Parameters[0].Value, "decrypted")
+	assert.Nil(t, output.NextToken)
+
+	_, err = fake.GetParametersByPath(ctx, &ssm.GetParametersByPathInput{Path: aws.String("app")})
+	assert.Error(t, err)
+}
+
+func TestPutParameter(t *testing.T) {
+	t.Parallel()
+
+	fake := NewSSM()
+	ctx := context.Background()
+
+	output, err := fake.PutParameter(ctx, &ssm.PutParameterInput{Name: aws.String("/app/a"), Value: aws.String("1")})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), output.Version)
+
+	_, err = fake.PutParameter(ctx, &ssm.PutParameterInput{Name: aws.String("/app/a"), Value: aws.String("2")})
+	var alreadyExists *types.ParameterAlreadyExists
+	assert.ErrorAs(t, err, &alreadyExists)
+
+	output, err = fake.PutParameter(ctx, &ssm.PutParameterInput{
+		Name:      aws.String("/app/a"),
+		Value:     aws.String("2"),
+		Type:      types.ParameterTypeSecureString,
+		Overwrite: aws.Bool(true),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), output.Version)
+
+	page, err := fake.GetParametersByPath(ctx, &ssm.GetParametersByPathInput{
+		Path:           aws.String("/app"),
+		WithDecryption: aws.Bool(true),
+	})
+	require.NoError(t, err)
+	require.Len(t, page.Parameters, 1)
+	assert.Equal(t, "2", *page.Parameters[0].Value)
+	assert.Equal(t, types.ParameterTypeSecureString, page.Parameters[0].Type)
+
+	_, err = fake.PutParameter(ctx, &ssm.PutParameterInput{Name: aws.String("/app/empty"), Value: aws.String("")})
+	assert.Error(t, err)
+}
+
+func TestGetParameters(t *testing.T) {
+	t.Parallel()
+
+	fake := NewSSM()
+	fake.Put("/app/a", "1")
+	fake.PutWithType("/app/secret", "password", types.ParameterTypeSecureString)
+
+	ctx := context.Background()
+
+	output, err := fake.GetParameters(ctx, &ssm.GetParametersInput{
+		Names:          []string{"/app/secret", "/app/missing", "/app/a"},
+		WithDecryption: aws.Bool(true),
+	})
+	require.NoError(t, err)
+	require.Len(t, output.Parameters, 2)
+	assert.Equal(t, "/app/secret", *output.Parameters[0].Name)
+	assert.Equal(t, "password", *output.Parameters[0].Value, "decrypted")
+	assert.Equal(t, "/app/a", *output.Parameters[1].Name)
+	assert.Equal(t, []string{"/app/missing"}, output.InvalidParameters)
+
+	_, err = fake.GetParameters(ctx, &ssm.GetParametersInput{Names: make([]string, 11)})
+	assert.Error(t, err)
+}
+
+func TestGetParametersWithSelectors(t *testing.T) {
+	t.Parallel()
+
+	fake := NewSSM()
+	fake.Put("/app/a", "1")
+	fake.Put("/app/a", "2")
+	fake.Put("/app/a", "3")
+
+	ctx := context.Background()
+
+	output, err := fake.LabelParameterVersion(ctx, &ssm.LabelParameterVersionInput{
+		Name:             aws.String("/app/a"),
+		ParameterVersion: aws.Int64(2),
+		Labels:           []string{"stable", "42", "aws-label"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"42", "aws-label"}, output.InvalidLabels)
+
+	_, err = fake.LabelParameterVersion(ctx, &ssm.LabelParameterVersionInput{
+		Name:             aws.String("/app/a"),
+		ParameterVersion: aws.Int64(4),
+		Labels:           []string{"stable"},
+	})
+	var versionNotFound *types.ParameterVersionNotFound
+	assert.ErrorAs(t, err, &versionNotFound)
+
+	parameters, err := fake.GetParameters(ctx, &ssm.GetParametersInput{
+		Names: []string{"/app/a:stable", "/app/a:1", "/app/a", "/app/a:beta", "/app/a:4"},
+	})
+	require.NoError(t, err)
+	require.Len(t, parameters.Parameters, 3)
+	assert.Equal(t, "2", *parameters.Parameters[0].Value)
+	assert.Equal(t, ":stable", *parameters.Parameters[0].Selector)
+	assert.Equal(t, "/app/a", *parameters.Parameters[0].Name)
+	assert.Equal(t, "1", *parameters.Parameters[1].Value)
+	assert.Equal(t, int64(1), parameters.Parameters[1].Version)
+	assert.Equal(t, "3", *parameters.Parameters[2].Value)
+	assert.Nil(t, parameters.Parameters[2].Selector)
+	assert.Equal(t, []string{"/app/a:beta", "/app/a:4"}, parameters.InvalidParameters)
+
+	_, err = fake.LabelParameterVersion(ctx, &ssm.LabelParameterVersionInput{
+		Name:   aws.String("/app/a"),
+		Labels: []string{"stable"},
+	})
+	require.NoError(t, err)
+	parameters, err = fake.GetParameters(ctx, &ssm.GetParametersInput{Names: []string{"/app/a:stable"}})
+	require.NoError(t, err)
+	assert.Equal(t, "3", *parameters.Parameters[0].Value, "label moved to the latest version")
+}
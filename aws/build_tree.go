@@ -1,8 +1,11 @@
 package aws
 
 import (
+	"encoding/json"
+	"strconv"
 	"strings"
 
+	"github.com/railsware/go-global/v2"
 	"github.com/railsware/go-global/v2/tree"
 )
 
@@ -27,6 +30,64 @@ func buildParamTree(params []param) *tree.Node {
 			destination = newDestination
 		}
 		destination.Value = param.value
+		destination.Origin = param.origin
 	}
 	return paramTree
 }
+
+// Builds a tree of secrets. A secret whose value is a JSON object is expanded into children.
+func buildSecretTree(secrets []secret) *tree.Node {
+	secretTree := &tree.Node{Children: make(map[string]*tree.Node)}
+
+	for _, secret := range secrets {
+		secretNode := buildSecretNode(secret.value, secret.origin)
+		pathParts := strings.Split(secret.path, paramStoreSeparator)
+		for index := len(pathParts) - 1; index >= 0; index-- {
+			if pathParts[index] != "" {
+				secretNode = &tree.Node{Children: map[string]*tree.Node{pathParts[index]: secretNode}}
+			}
+		}
+		secretTree = tree.Merge(secretTree, secretNode)
+	}
+	return secretTree
+}
+
+func buildSecretNode(value string, origin *global.Origin) *tree.Node {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	var object map[string]interface{}
+	if !strings.HasPrefix(strings.TrimSpace(value), "{") || decoder.Decode(&object) != nil {
+		return &tree.Node{Value: value, Origin: origin}
+	}
+	return buildJSONNode(object, origin)
+}
+
+// Converts a decoded JSON value to a node. Returns nil for null.
+func buildJSONNode(value interface{}, origin *global.Origin) *tree.Node {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		node := &tree.Node{Children: make(map[string]*tree.Node, len(value))}
+		for key, child := range value {
+			if childNode := buildJSONNode(child, origin); childNode != nil {
+				node.Children[key] = childNode
+			}
+		}
+		return node
+	case []interface{}:
+		node := &tree.Node{Children: make(map[string]*tree.Node, len(value))}
+		for index, child := range value {
+			if childNode := buildJSONNode(child, origin); childNode != nil {
+				node.Children[strconv.Itoa(index)] = childNode
+			}
+		}
+		return node
+	case string:
+		return &tree.Node{Value: value, Origin: origin}
+	case json.Number:
+		return &tree.Node{Value: value.String(), Origin: origin}
+	case bool:
+		return &tree.Node{Value: strconv.FormatBool(value), Origin: origin}
+	default:
+		return nil
+	}
+}
@@ -3,6 +3,7 @@ package aws
 import (
 	"testing"
 
+	"github.com/railsware/go-global/v2"
 	"github.com/railsware/go-global/v2/tree"
 	"github.com/stretchr/testify/assert"
 )
@@ -10,16 +11,17 @@ import (
 func TestBuildTree(t *testing.T) {
 	t.Parallel()
 
+	origin := &global.Origin{Name: "/app/String", Prefix: "/app/"}
 	params := []param{
-		{"String", "string"},
-		{"NestedSimple/Nested", "nested_string"},
-		{"NestedDeep/SecondLevel/Nested", "deep_string"},
-		{"NestedDeep/SecondLevel/Nested2", "deep_string_2"},
+		{path: "String", value: "string", origin: origin},
+		{path: "NestedSimple/Nested", value: "nested_string"},
+		{path: "NestedDeep/SecondLevel/Nested", value: "deep_string"},
+		{path: "NestedDeep/SecondLevel/Nested2", value: "deep_string_2"},
 	}
 
 	expectedTree := &tree.Node{
 		Children: map[string]*tree.Node{
-			"String": {Value: "string"},
+			"String": {Value: "string", Origin: origin},
 			"NestedSimple": {
 				Children: map[string]*tree.Node{
 					"Nested": {Value: "nested_string"},
@@ -42,3 +44,33 @@ func TestBuildTree(t *testing.T) {
 
 	assert.Equal(t, expectedTree, paramTree)
 }
+
+func TestBuildSecretTree(t *testing.T) {
+	t.Parallel()
+
+	secrets := []secret{
+		{path: "plain", value: "string"},
+		{path: "number", value: "42"},
+		{path: "db/main", value: `{"port": 5432, "ssl": true, "comment": null, "hosts": ["a"]}`},
+	}
+
+	expectedTree := &tree.Node{
+		Children: map[string]*tree.Node{
+			"plain":  {Value: "string"},
+			"number": {Value: "42"},
+			"db": {
+				Children: map[string]*tree.Node{
+					"main": {
+						Children: map[string]*tree.Node{
+							"port":  {Value: "5432"},
+							"ssl":   {Value: "true"},
+							"hosts": {Children: map[string]*tree.Node{"0": {Value: "a"}}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, expectedTree, buildSecretTree(secrets))
+}
@@ -0,0 +1,91 @@
+package aws
+
+import (
+	"reflect"
+	"regexp"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/tree"
+	"github.com/railsware/go-global/v2/utils"
+)
+
+// LabelPolicy decides what happens if a param has no version with its label, see LoadConfigOptions.Label.
+type LabelPolicy int
+
+const (
+	// LabelRequired fails loading.
+	LabelRequired LabelPolicy = iota
+	// LabelFallbackToLatest uses the latest version of the param, with a warning.
+	LabelFallbackToLatest
+)
+
+const selectorSeparator = ":"
+
+// Labels consist of letters, numbers, periods, hyphens and underscores; numbers select versions.
+var validLabel = regexp.MustCompile(`^[a-zA-Z0-9_.\-]+$`)
+
+// Returns the label of the param of a field: the `label:` tag of the field, or the label of the options.
+func (options LoadConfigOptions) paramLabel(fieldLabel string) string {
+	if fieldLabel != "" {
+		return fieldLabel
+	}
+	return options.Label
+}
+
+// Returns the name to request with GetParameters, e.g. "/app/a:stable", or the name itself if there is no label.
+func selectParam(name string, label string) string {
+	if label == "" {
+		return name
+	}
+	return name + selectorSeparator + label
+}
+
+// Returns an error if a field of config is tagged with `label:`, as labels are only used when fetching by name.
+// Invalid configs are left to be reported when writing.
+func checkNoLabelTags(config interface{}) global.Error {
+	reflectedConfig, err := utils.ReflectConfig(config)
+	if err != nil {
+		return nil
+	}
+	fieldPath := findLabelTag(reflectedConfig.Type(), "", map[reflect.Type]bool{})
+	if fieldPath == "" {
+		return nil
+	}
+	return global.WrapError(
+		tree.ErrInvalidTag,
+		"global: %s: label tag needs FetchByName or Label to be set",
+		fieldPath,
+	)
+}
+
+// Returns the Go path of the first field tagged with `label:` in a value of the type, or "" if there is none.
+// visiting holds the struct types being searched up the stack, so that recursive types are not searched forever.
+func findLabelTag(valueType reflect.Type, fieldPath string, visiting map[reflect.Type]bool) string {
+	switch valueType.Kind() { //nolint:exhaustive // other kinds have no fields
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return findLabelTag(valueType.Elem(), fieldPath, visiting)
+	case reflect.Struct:
+		if visiting[valueType] {
+			return ""
+		}
+		visiting[valueType] = true
+		defer delete(visiting, valueType)
+
+		for _, structField := range reflect.VisibleFields(valueType) {
+			if !structField.IsExported() || structField.Anonymous {
+				continue
+			}
+			childFieldPath := structField.Name
+			if fieldPath != "" {
+				childFieldPath = fieldPath + "." + structField.Name
+			}
+			if structField.Tag.Get("label") != "" {
+				return childFieldPath
+			}
+			if found := findLabelTag(structField.Type, childFieldPath, visiting); found != "" {
+				return found
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,145 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/aws/awstest"
+	"github.com/railsware/go-global/v2/tree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type labelledConfig struct {
+	Database struct {
+		PoolSize int    `json:"pool_size"`
+		User     string `json:"user"`
+		Timeout  int    `json:"timeout" label:"1"`
+	} `json:"database"`
+}
+
+func newLabelledSSM(t *testing.T) *awstest.SSM {
+	t.Helper()
+
+	fake := awstest.NewSSM()
+	fake.Put("/app/database/pool_size", "5")
+	fake.Put("/app/database/pool_size", "10")
+	fake.Put("/app/database/pool_size", "20")
+	fake.Put("/app/database/user", "app")
+	fake.Put("/app/database/timeout", "30")
+	fake.Put("/app/database/timeout", "60")
+
+	_, err := fake.LabelParameterVersion(context.Background(), &ssm.LabelParameterVersionInput{
+		Name:             aws.String("/app/database/pool_size"),
+		ParameterVersion: aws.Int64(2),
+		Labels:           []string{"stable"},
+	})
+	require.NoError(t, err)
+	return fake
+}
+
+func TestLoadConfigWithLabel(t *testing.T) {
+	t.Parallel()
+
+	var config labelledConfig
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: newLabelledSSM(t), Label: "stable"},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.False(t, err.Warning())
+	assert.ErrorIs(t, err, ErrLabelNotFound)
+	assert.NotErrorIs(t, err, ErrParamNotFound)
+	assert.Equal(t, "global: label not found /app/database/user:stable of field Database.User", err.Error())
+
+	provenance := make(global.Provenance)
+	err = LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{
+			ParamPrefix:  "/app/",
+			Client:       newLabelledSSM(t),
+			Label:        "stable",
+			MissingLabel: LabelFallbackToLatest,
+			Provenance:   provenance,
+		},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.True(t, err.Warning())
+	assert.Equal(
+		t,
+		"global: label not found /app/database/user:stable of field Database.User, using the latest version",
+		err.Error(),
+	)
+
+	var labelErr *ParamNameError
+	require.ErrorAs(t, err, &labelErr)
+	assert.Equal(t, "stable", labelErr.Label)
+	assert.True(t, labelErr.UsedLatest)
+
+	assert.Equal(t, 10, config.Database.PoolSize)
+	assert.Equal(t, "app", config.Database.User)
+	assert.Equal(t, 30, config.Database.Timeout, "pinned to version 1 by the tag")
+
+	origin, ok := provenance["Database.PoolSize"]
+	require.True(t, ok)
+	assert.Equal(t, "/app/database/pool_size", origin.Name)
+	assert.Equal(t, "2", origin.Version)
+}
+
+func TestLoadConfigWithFieldLabels(t *testing.T) {
+	t.Parallel()
+
+	var config labelledConfig
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: newLabelledSSM(t), FetchByName: true},
+		&config,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, 20, config.Database.PoolSize, "latest version without a label")
+	assert.Equal(t, 30, config.Database.Timeout)
+
+	var missingConfig struct {
+		Host string `json:"host" label:"stable"`
+	}
+	err = LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: newLabelledSSM(t), FetchByName: true},
+		&missingConfig,
+	)
+	require.NotNil(t, err)
+	assert.True(t, err.Warning())
+	assert.ErrorIs(t, err, ErrParamNotFound, "the param doesn't exist at all")
+
+	var invalidConfig struct {
+		Host string `json:"host" label:"not:valid"`
+	}
+	err = LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: newLabelledSSM(t), FetchByName: true},
+		&invalidConfig,
+	)
+	require.NotNil(t, err)
+	assert.ErrorIs(t, err, ErrInvalidParamName)
+}
+
+func TestLoadConfigWithFieldLabelsByPath(t *testing.T) {
+	t.Parallel()
+
+	var config labelledConfig
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: newLabelledSSM(t)},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.False(t, err.Warning())
+	assert.ErrorIs(t, err, tree.ErrInvalidTag)
+	assert.Equal(t, "global: Database.Timeout: label tag needs FetchByName or Label to be set", err.Error())
+	assert.Zero(t, config.Database.PoolSize, "nothing is loaded")
+}
@@ -22,11 +22,16 @@ var (
 // - panics if anything goes wrong
 // The suggested application is in the initialization of an AWS ECS service or Lambda function.
 func MustLoadConfig(config interface{}) {
+	MustLoadConfigWithContext(context.Background(), config)
+}
+
+// MustLoadConfigWithContext is MustLoadConfig that stops loading, and panics, when ctx is done.
+func MustLoadConfigWithContext(ctx context.Context, config interface{}) {
 	if reflect.ValueOf(config).Kind() != reflect.Ptr {
 		panic(errConfigMustBeAPointer)
 	}
 
-	awsConfig, err := awsConfig.LoadDefaultConfig(context.Background())
+	awsConfig, err := awsConfig.LoadDefaultConfig(ctx)
 	if err != nil {
 		panic(fmt.Errorf("paramstore.MustLoadConfig: Cannot load AWS config: %w", err))
 	}
@@ -34,7 +39,8 @@ func MustLoadConfig(config interface{}) {
 	if awsParamPrefix == "" {
 		panic(errParamPrefixRequired)
 	}
-	configErr := LoadConfigFromParameterStore(
+	configErr := LoadConfigFromParameterStoreWithContext(
+		ctx,
 		awsConfig,
 		LoadConfigOptions{
 			ParamPrefix:          awsParamPrefix,
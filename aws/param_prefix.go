@@ -0,0 +1,78 @@
+package aws
+
+import (
+	"strings"
+
+	"github.com/railsware/go-global/v2"
+)
+
+// Normalises the prefix to the form "/a/b/": with leading and trailing slashes and no duplicate slashes.
+// The root prefix "/" stays as it is.
+func normalizeParamPrefix(prefix string) string {
+	parts := strings.Split(prefix, paramStoreSeparator)
+	nonEmptyParts := parts[:0]
+	for _, part := range parts {
+		if part != "" {
+			nonEmptyParts = append(nonEmptyParts, part)
+		}
+	}
+	if len(nonEmptyParts) == 0 {
+		return paramStoreSeparator
+	}
+	return paramStoreSeparator + strings.Join(nonEmptyParts, paramStoreSeparator) + paramStoreSeparator
+}
+
+// Returns an error if the prefix is empty, as it would select every param of the account.
+// The root has to be asked for explicitly with "/".
+func checkParamPrefix(prefix string) global.Error {
+	if prefix == "" {
+		return global.NewError(`global: parameter prefix is empty, use "/" for all parameters`)
+	}
+	return nil
+}
+
+// Returns the path to request from Parameter Store for the normalised prefix, i.e. without the trailing slash.
+func paramPath(normalizedPrefix string) string {
+	if normalizedPrefix == paramStoreSeparator {
+		return normalizedPrefix
+	}
+	return strings.TrimSuffix(normalizedPrefix, paramStoreSeparator)
+}
+
+// PrefixMount places the params stored under ParamPrefix at MountPath of the config.
+type PrefixMount struct {
+	// ParamPrefix is the path of the params, e.g. "/shared/".
+	// Leading and trailing slashes are optional.
+	ParamPrefix string
+	// MountPath is the slash-separated path of the config to place the params at, e.g. "database".
+	// If empty, the params are placed at the root of the config.
+	MountPath string
+}
+
+// Returns all prefixes to load, in order. ParamPrefix comes first,
+// and is loaded even if empty unless ParamPrefixes are set.
+func (options LoadConfigOptions) prefixMounts() []PrefixMount {
+	mounts := make([]PrefixMount, 0, len(options.ParamPrefixes)+1)
+	if options.ParamPrefix != "" || len(options.ParamPrefixes) == 0 {
+		mounts = append(mounts, PrefixMount{ParamPrefix: options.ParamPrefix})
+	}
+	return append(mounts, options.ParamPrefixes...)
+}
+
+// Returns the name of the param of the config path under the last mount covering the path,
+// which is used to name the params missing from Parameter Store.
+func (options LoadConfigOptions) paramName(path string) string {
+	paramPrefix, mountPath := "", ""
+	for _, mount := range options.prefixMounts() {
+		if candidatePath := mountPathPrefix(mount.MountPath); strings.HasPrefix(path, candidatePath) {
+			paramPrefix, mountPath = mount.ParamPrefix, candidatePath
+		}
+	}
+	return normalizeParamPrefix(paramPrefix) + strings.TrimPrefix(path, mountPath)
+}
+
+// Normalises the mount path to the form "a/b/", or "" for the root of the config.
+func mountPathPrefix(mountPath string) string {
+	normalized := normalizeParamPrefix(mountPath)
+	return strings.TrimPrefix(normalized, paramStoreSeparator)
+}
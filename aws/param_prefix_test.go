@@ -0,0 +1,144 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamName(t *testing.T) {
+	t.Parallel()
+
+	options := LoadConfigOptions{
+		ParamPrefix:   "app",
+		ParamPrefixes: []PrefixMount{{ParamPrefix: "/db/", MountPath: "database"}},
+	}
+	assert.Equal(t, "/app/host", options.paramName("host"))
+	assert.Equal(t, "/db/host", options.paramName("database/host"))
+	assert.Equal(t, "/app/databases/host", options.paramName("databases/host"))
+}
+
+func TestNormalizeParamPrefix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		prefix           string
+		normalizedPrefix string
+		path             string
+	}{
+		{"", "/", "/"},
+		{"/", "/", "/"},
+		{"//", "/", "/"},
+		{"app", "/app/", "/app"},
+		{"/app", "/app/", "/app"},
+		{"app/", "/app/", "/app"},
+		{"/app/", "/app/", "/app"},
+		{"//app//", "/app/", "/app"},
+		{"/app/production", "/app/production/", "/app/production"},
+		{"app//production/", "/app/production/", "/app/production"},
+	}
+
+	for _, test := range tests {
+		normalizedPrefix := normalizeParamPrefix(test.prefix)
+		assert.Equal(t, test.normalizedPrefix, normalizedPrefix, "prefix %q", test.prefix)
+		assert.Equal(t, test.path, paramPath(normalizedPrefix), "prefix %q", test.prefix)
+	}
+}
+
+func TestLoadConfigWithPrefixVariants(t *testing.T) {
+	t.Parallel()
+
+	for _, prefix := range []string{"/app/", "/app", "app", "app/", "//app//"} {
+		var config paramStoreConfig
+		err := LoadConfigFromParameterStore(
+			aws.Config{},
+			LoadConfigOptions{ParamPrefix: prefix, Client: newFakeSSM(), IgnoreUnmappedParams: true},
+			&config,
+		)
+		require.Nil(t, err, "prefix %q", prefix)
+		assert.Equal(t, 10, config.Database.PoolSize, "prefix %q", prefix)
+		assert.Equal(t, []string{"postgres://primary", "postgres://replica"}, config.Database.URLs, "prefix %q", prefix)
+	}
+}
+
+func TestLoadConfigWithNestedPrefix(t *testing.T) {
+	t.Parallel()
+
+	var config struct {
+		PoolSize int `json:"pool_size"`
+	}
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/database", Client: newFakeSSM(), IgnoreUnmappedParams: true},
+		&config,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, 10, config.PoolSize)
+}
+
+// Returns params regardless of the requested path.
+type staticSSMClient []types.Parameter
+
+func (client staticSSMClient) GetParametersByPath(
+	context.Context,
+	*ssm.GetParametersByPathInput,
+	...func(*ssm.Options),
+) (*ssm.GetParametersByPathOutput, error) {
+	return &ssm.GetParametersByPathOutput{Parameters: client}, nil
+}
+
+func TestLoadConfigRejectsParamsNotMatchingPrefix(t *testing.T) {
+	t.Parallel()
+
+	client := staticSSMClient{
+		{Name: aws.String("/app/pool_size"), Value: aws.String("10")},
+		{Name: aws.String("/application/pool_size"), Value: aws.String("20")},
+	}
+
+	var config struct {
+		PoolSize int `json:"pool_size"`
+	}
+	err := LoadConfigFromParameterStore(aws.Config{}, LoadConfigOptions{ParamPrefix: "/app", Client: client}, &config)
+	require.NotNil(t, err)
+	assert.Equal(t, "global: parameter /application/pool_size does not match prefix /app/", err.Error())
+}
+
+func TestLoadConfigWithEmptyPrefix(t *testing.T) {
+	t.Parallel()
+
+	var config paramStoreConfig
+	for _, options := range []LoadConfigOptions{
+		{Client: failingSSMClient{}},
+		{ParamPrefixes: []PrefixMount{{ParamPrefix: "/app/"}, {MountPath: "database"}}, Client: failingSSMClient{}},
+	} {
+		err := LoadConfigFromParameterStore(aws.Config{}, options, &config)
+		require.NotNil(t, err)
+		assert.NotErrorIs(t, err, errSSMUnavailable, "nothing is fetched")
+		assert.Equal(t, `global: parameter prefix is empty, use "/" for all parameters`, err.Error())
+	}
+
+	_, err := PutConfigToParameterStore(
+		context.Background(),
+		aws.Config{},
+		PutConfigOptions{Client: newFakeSSM()},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "parameter prefix is empty")
+
+	var rootConfig struct {
+		App paramStoreConfig `json:"app"`
+	}
+	err = LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/", Client: newFakeSSM(), IgnoreUnmappedParams: true},
+		&rootConfig,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, 10, rootConfig.App.Database.PoolSize)
+}
@@ -0,0 +1,252 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/tree"
+)
+
+// GetParameters accepts up to 10 names per request.
+const getParametersBatchSize = 10
+
+// Kinds of ParamNameError, to be matched with errors.Is.
+var (
+	ErrInvalidParamName = errors.New("invalid parameter name")
+	ErrParamNotFound    = errors.New("parameter not found")
+	ErrLabelNotFound    = errors.New("label not found")
+)
+
+var validParamName = regexp.MustCompile(`^/[a-zA-Z0-9_.\-/]+$`)
+
+// ParamNameError describes a param that could not be fetched by name, see LoadConfigOptions.FetchByName,
+// or put, see PutConfigToParameterStore.
+type ParamNameError struct {
+	// Name of the param in Parameter Store.
+	Name string
+	// Label of the param, if any, see LoadConfigOptions.Label.
+	Label string
+	// FieldPath is the path of the config field the param is written into, e.g. Database.PoolSize, if known.
+	FieldPath string
+	// Kind is ErrInvalidParamName, ErrParamNotFound or ErrLabelNotFound.
+	Kind error
+	// UsedLatest is set if the latest version of the param was used instead of the missing label,
+	// see LabelFallbackToLatest.
+	UsedLatest bool
+}
+
+func (err *ParamNameError) Error() string {
+	msg := fmt.Sprintf("global: %v %s", err.Kind, selectParam(err.Name, err.Label))
+	if err.FieldPath != "" {
+		msg += " of field " + err.FieldPath
+	}
+	if err.UsedLatest {
+		msg += ", using the latest version"
+	}
+	return msg
+}
+
+func (err *ParamNameError) Unwrap() error {
+	return err.Kind
+}
+
+// Warning reports whether the param was not found, or its latest version was used instead of the label.
+// Fields without a param keep their zero or default value, unless they are required.
+func (err *ParamNameError) Warning() bool {
+	return err.Kind == ErrParamNotFound || err.UsedLatest
+}
+
+// A param to fetch by name for a config field.
+type namedParam struct {
+	mountIndex  int
+	paramPrefix string
+	configParam tree.ConfigParam
+	name        string
+	label       string
+}
+
+// Returns the name to request, with the label selector if any.
+func (namedParam namedParam) selector() string {
+	return selectParam(namedParam.name, namedParam.label)
+}
+
+// Fetches params of all mounts by the names derived from config fields, in batches.
+// A field under several mounts is requested from each of them, so that later mounts override earlier ones.
+func fetchMountedParamsByName(
+	ctx context.Context,
+	client SSMGetClient,
+	mounts []PrefixMount,
+	configParams []tree.ConfigParam,
+	options LoadConfigOptions,
+) ([][]param, global.Error) {
+	namedParams, err := nameParams(mounts, configParams, options)
+	if err != nil {
+		return nil, err
+	}
+
+	selectors := make([]string, 0, len(namedParams))
+	for _, namedParam := range namedParams {
+		selectors = append(selectors, namedParam.selector())
+	}
+	ssmParams, err := fetchParamsByName(ctx, client, uniqueStrings(selectors), options.Retry)
+	if err != nil {
+		return nil, err
+	}
+
+	// find out which of the labelled params that were not found exist without the label
+	var unlabelledNames []string
+	for _, namedParam := range namedParams {
+		if _, ok := ssmParams[namedParam.selector()]; !ok && namedParam.label != "" {
+			unlabelledNames = append(unlabelledNames, namedParam.name)
+		}
+	}
+	latestParams, err := fetchParamsByName(ctx, client, uniqueStrings(unlabelledNames), options.Retry)
+	if err != nil {
+		return nil, err
+	}
+
+	paramsByMount := make([][]param, len(mounts))
+	found := make(map[string]bool, len(configParams))
+	lastNames := make(map[string]string, len(configParams))
+	var errs []global.Error
+	for _, namedParam := range namedParams {
+		path := namedParam.configParam.Path
+		lastNames[path] = namedParam.name
+		ssmParam, ok := ssmParams[namedParam.selector()]
+		if !ok && namedParam.label != "" {
+			if ssmParam, ok = latestParams[namedParam.name]; ok {
+				labelErr := &ParamNameError{
+					Name:       namedParam.name,
+					Label:      namedParam.label,
+					FieldPath:  namedParam.configParam.FieldPath,
+					Kind:       ErrLabelNotFound,
+					UsedLatest: options.MissingLabel == LabelFallbackToLatest,
+				}
+				errs = append(errs, labelErr)
+				// the param exists, so it is not reported as not found
+				found[path] = true
+				ok = labelErr.UsedLatest
+			}
+		}
+		if !ok {
+			continue
+		}
+		found[path] = true
+		paramsByMount[namedParam.mountIndex] = append(
+			paramsByMount[namedParam.mountIndex],
+			newParam(ssmParam, path, namedParam.paramPrefix),
+		)
+	}
+
+	for _, configParam := range configParams {
+		name, ok := lastNames[configParam.Path]
+		fieldTag := configParam.FieldTag
+		// missing required params are reported when writing the config
+		if !ok || found[configParam.Path] || fieldTag.Required || fieldTag.HasDefault || options.RequireAllParams {
+			continue
+		}
+		errs = append(errs, &ParamNameError{Name: name, FieldPath: configParam.FieldPath, Kind: ErrParamNotFound})
+	}
+
+	err = global.JoinErrors(errs...)
+	if err != nil && !err.Warning() {
+		return nil, err
+	}
+	return paramsByMount, err
+}
+
+// Names the params of config fields under each mount. Returns an error if any name is invalid.
+func nameParams(
+	mounts []PrefixMount,
+	configParams []tree.ConfigParam,
+	options LoadConfigOptions,
+) ([]namedParam, global.Error) {
+	var namedParams []namedParam
+	var errs []global.Error
+
+	for mountIndex, mount := range mounts {
+		paramPrefix := normalizeParamPrefix(mount.ParamPrefix)
+		mountPath := mountPathPrefix(mount.MountPath)
+		for _, configParam := range configParams {
+			if !strings.HasPrefix(configParam.Path, mountPath) || len(configParam.Path) == len(mountPath) {
+				continue
+			}
+			name := paramPrefix + configParam.Path[len(mountPath):]
+			label := options.paramLabel(configParam.FieldTag.Label)
+			if !validParamName.MatchString(name) || (label != "" && !validLabel.MatchString(label)) {
+				errs = append(errs, &ParamNameError{
+					Name: name, Label: label, FieldPath: configParam.FieldPath, Kind: ErrInvalidParamName,
+				})
+				continue
+			}
+			namedParams = append(namedParams, namedParam{
+				mountIndex:  mountIndex,
+				paramPrefix: paramPrefix,
+				configParam: configParam,
+				name:        name,
+				label:       label,
+			})
+		}
+	}
+
+	if err := global.JoinErrors(errs...); err != nil {
+		return nil, err
+	}
+	return namedParams, nil
+}
+
+// Fetches the params with GetParameters, decrypted, by names with optional selectors, e.g. "/app/a:stable".
+// Returns the params by the requested names. Names that are not found are left out of the result.
+func fetchParamsByName(
+	ctx context.Context,
+	client SSMGetClient,
+	names []string,
+	retryPolicy RetryPolicy,
+) (map[string]types.Parameter, global.Error) {
+	ssmParams := make(map[string]types.Parameter, len(names))
+
+	for start := 0; start < len(names); start += getParametersBatchSize {
+		end := start + getParametersBatchSize
+		if end > len(names) {
+			end = len(names)
+		}
+		input := &ssm.GetParametersInput{
+			Names:          names[start:end],
+			WithDecryption: aws.Bool(true),
+		}
+
+		output, err := retry(ctx, retryPolicy, func() (*ssm.GetParametersOutput, error) {
+			return client.GetParameters(ctx, input)
+		})
+		if err != nil {
+			return nil, wrapLoadError(ctx, err, "Parameter Store")
+		}
+		for _, ssmParam := range output.Parameters {
+			// the selector is returned with a leading colon, e.g. ":stable"
+			label := strings.TrimPrefix(aws.ToString(ssmParam.Selector), selectorSeparator)
+			ssmParams[selectParam(aws.ToString(ssmParam.Name), label)] = ssmParam
+		}
+	}
+
+	return ssmParams, nil
+}
+
+// Returns the strings without duplicates, in the order of their first occurrence.
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,219 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/railsware/go-global/v2/aws/awstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Records the names of GetParameters requests, and fails GetParametersByPath.
+type namesOnlySSMClient struct {
+	*awstest.SSM
+
+	mutex    sync.Mutex
+	requests [][]string
+}
+
+func (c *namesOnlySSMClient) GetParametersByPath(
+	context.Context,
+	*ssm.GetParametersByPathInput,
+	...func(*ssm.Options),
+) (*ssm.GetParametersByPathOutput, error) {
+	return nil, errors.New("access denied to the path")
+}
+
+func (c *namesOnlySSMClient) GetParameters(
+	ctx context.Context,
+	params *ssm.GetParametersInput,
+	optFns ...func(*ssm.Options),
+) (*ssm.GetParametersOutput, error) {
+	c.mutex.Lock()
+	c.requests = append(c.requests, params.Names)
+	c.mutex.Unlock()
+	return c.SSM.GetParameters(ctx, params, optFns...)
+}
+
+type byNameConfig struct {
+	Database struct {
+		PoolSize int      `json:"pool_size"`
+		URLs     []string `json:"urls" split:","`
+		Host     string   `json:"host"`
+		Port     int      `json:"port" default:"5432"`
+		User     string   `json:"user" global:",required"`
+	} `json:"database"`
+	Features [10]bool `json:"features"`
+}
+
+func newByNameSSM() *awstest.SSM {
+	fake := awstest.NewSSM()
+	fake.Put("/app/database/pool_size", "10")
+	fake.PutWithType("/app/database/urls", "postgres://primary,postgres://replica", types.ParameterTypeStringList)
+	fake.Put("/app/database/user", "app")
+	fake.Put("/app/unrelated", "foo")
+	fake.Put("/db/user", "db_user")
+	return fake
+}
+
+func TestLoadConfigFetchByName(t *testing.T) {
+	t.Parallel()
+
+	client := &namesOnlySSMClient{SSM: newByNameSSM()}
+
+	var config byNameConfig
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: client, FetchByName: true},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.True(t, err.Warning())
+	assert.ErrorIs(t, err, ErrParamNotFound)
+
+	var nameErr *ParamNameError
+	require.ErrorAs(t, err, &nameErr)
+	assert.Equal(t, "/app/database/host", nameErr.Name)
+	assert.Equal(t, "Database.Host", nameErr.FieldPath)
+	assert.Contains(t, err.Error(), "global: parameter not found /app/database/host of field Database.Host")
+	assert.NotContains(t, err.Error(), "/app/database/port", "has a default")
+	assert.NotContains(t, err.Error(), "/app/unrelated", "not requested")
+
+	assert.Equal(t, 10, config.Database.PoolSize)
+	assert.Equal(t, []string{"postgres://primary", "postgres://replica"}, config.Database.URLs)
+	assert.Equal(t, 5432, config.Database.Port)
+	assert.Equal(t, "app", config.Database.User)
+
+	require.Len(t, client.requests, 2, "15 names in batches of 10")
+	assert.Equal(t, []string{
+		"/app/database/pool_size",
+		"/app/database/urls",
+		"/app/database/host",
+		"/app/database/port",
+		"/app/database/user",
+	}, client.requests[0][:5])
+	assert.Len(t, client.requests[1], 5)
+}
+
+func TestLoadConfigFetchByNameWithMounts(t *testing.T) {
+	t.Parallel()
+
+	client := &namesOnlySSMClient{SSM: newByNameSSM()}
+
+	var config byNameConfig
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{
+			ParamPrefix:      "/app/",
+			ParamPrefixes:    []PrefixMount{{ParamPrefix: "/db/", MountPath: "database"}},
+			Client:           client,
+			FetchByName:      true,
+			RequireAllParams: true,
+		},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.NotErrorIs(t, err, ErrParamNotFound, "reported as missing when writing")
+	assert.Contains(t, err.Error(), "database/host: missing parameter /db/host")
+
+	assert.Equal(t, 10, config.Database.PoolSize)
+	assert.Equal(t, "db_user", config.Database.User, "later mount overrides")
+}
+
+func TestLoadConfigFetchByNameErrors(t *testing.T) {
+	t.Parallel()
+
+	client := &namesOnlySSMClient{SSM: newByNameSSM()}
+
+	var invalidConfig struct {
+		Host string `global:"host name"`
+	}
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: client, FetchByName: true},
+		&invalidConfig,
+	)
+	require.NotNil(t, err)
+	assert.False(t, err.Warning())
+	assert.ErrorIs(t, err, ErrInvalidParamName)
+	assert.Empty(t, client.requests)
+
+	var config byNameConfig
+	err = LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: failingSSMClient{}, FetchByName: true},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "needs a client with GetParameters")
+
+	var mapConfig struct {
+		Limits map[string]int `json:"limits"`
+	}
+	err = LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: client, FetchByName: true},
+		&mapConfig,
+	)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "limits: cannot list params of map")
+}
+
+func TestPutConfigThenLoadFetchByName(t *testing.T) {
+	t.Parallel()
+
+	type roundTripConfig struct {
+		Database struct {
+			PoolSize int      `json:"pool_size"`
+			URLs     []string `json:"urls" split:","`
+			Password string   `json:"password" global:",sensitive"`
+		} `json:"database"`
+		Features [2]bool `json:"features"`
+	}
+
+	var putConfig roundTripConfig
+	putConfig.Database.PoolSize = 20
+	putConfig.Database.URLs = []string{"postgres://primary", "postgres://replica"}
+	putConfig.Database.Password = "hunter2"
+	putConfig.Features = [2]bool{true, false}
+
+	fake := awstest.NewSSM()
+	_, err := PutConfigToParameterStore(
+		context.Background(),
+		aws.Config{},
+		PutConfigOptions{ParamPrefix: "/app/", Client: fake},
+		&putConfig,
+	)
+	require.Nil(t, err)
+
+	var config roundTripConfig
+	err = LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: &namesOnlySSMClient{SSM: fake}, FetchByName: true},
+		&config,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, putConfig, config)
+}
+
+func TestLoadConfigFetchByNameWithUnsplitSlice(t *testing.T) {
+	t.Parallel()
+
+	var config struct {
+		URLs []string `json:"urls"`
+	}
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: &namesOnlySSMClient{SSM: newByNameSSM()}, FetchByName: true},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.False(t, err.Warning())
+	assert.Contains(t, err.Error(), "urls: cannot list params of slice without split tag")
+}
@@ -0,0 +1,202 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/redact"
+	"github.com/railsware/go-global/v2/tree"
+)
+
+// OverwritePolicy decides what happens to params that already exist.
+type OverwritePolicy int
+
+const (
+	// OverwriteNone keeps existing params as they are.
+	OverwriteNone OverwritePolicy = iota
+	// OverwriteChanged updates existing params whose value or type differs.
+	OverwriteChanged
+	// OverwriteAll puts every param, creating new versions of unchanged ones too.
+	OverwriteAll
+)
+
+type PutConfigOptions struct {
+	// ParamPrefix is the path to put the params under, e.g. "/myapp/production/".
+	// Leading and trailing slashes are optional. Required; "/" puts the params at the root.
+	ParamPrefix string
+	// Client is used to access Parameter Store. If not set, a client is created from the AWS config.
+	Client SSMPutClient
+	// Overwrite decides what happens to params that already exist. By default, they are kept.
+	Overwrite OverwritePolicy
+	// If DryRun is set, nothing is put, the plan is only returned and printed.
+	DryRun bool
+	// PlanOutput, if set, receives the plan, one line per param.
+	PlanOutput io.Writer
+}
+
+// PlanAction is what is done to a single param.
+type PlanAction string
+
+const (
+	PlanCreate    PlanAction = "create"
+	PlanUpdate    PlanAction = "update"
+	PlanKeep      PlanAction = "keep"
+	PlanUnchanged PlanAction = "unchanged"
+)
+
+// PlannedParam is a param to put, or one left as it is.
+type PlannedParam struct {
+	Name   string
+	Type   types.ParameterType
+	Value  string
+	Action PlanAction
+}
+
+// Plan lists the params of the config, sorted by name.
+type Plan []PlannedParam
+
+// String lists the params one per line, e.g. "create /app/database/pool_size (String) = 10".
+// SecureString values are masked.
+func (plan Plan) String() string {
+	var builder strings.Builder
+	for _, plannedParam := range plan {
+		value := plannedParam.Value
+		if plannedParam.Type == types.ParameterTypeSecureString {
+			value = redact.Mask
+		}
+		fmt.Fprintf(&builder, "%s %s (%s) = %s\n", plannedParam.Action, plannedParam.Name, plannedParam.Type, value)
+	}
+	return builder.String()
+}
+
+// PutConfigToParameterStore puts the values of config into Parameter Store, the inverse of
+// LoadConfigFromParameterStore.
+//   - config must be a pointer to a struct.
+//   - Params are named like the loader matches them: by the `global:` tag, the `json:` tag or the field name,
+//     joined with slashes under options.ParamPrefix.
+//   - Values of fields tagged `global:",sensitive"` are put as SecureString, others as String.
+//   - Nil pointers, slices and maps, and empty strings are left out, as Parameter Store can't hold empty values.
+//
+// The plan is returned even if putting fails, with the params put so far.
+func PutConfigToParameterStore(
+	ctx context.Context,
+	awsConfig aws.Config,
+	options PutConfigOptions,
+	config interface{},
+) (Plan, global.Error) {
+	if err := checkParamPrefix(options.ParamPrefix); err != nil {
+		return nil, err
+	}
+	configTree, err := tree.ReadConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	client := options.Client
+	if client == nil {
+		client = ssm.NewFromConfig(awsConfig)
+	}
+
+	paramPrefix := normalizeParamPrefix(options.ParamPrefix)
+	existingParams, err := fetchParams(ctx, client, paramPrefix, RetryPolicy{})
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := planParams(configTree, paramPrefix, existingParams, options.Overwrite)
+	if err != nil {
+		return nil, err
+	}
+	if options.PlanOutput != nil {
+		fmt.Fprint(options.PlanOutput, plan)
+	}
+	if options.DryRun {
+		return plan, nil
+	}
+
+	for index, plannedParam := range plan {
+		if plannedParam.Action != PlanCreate && plannedParam.Action != PlanUpdate {
+			continue
+		}
+		_, putErr := client.PutParameter(ctx, &ssm.PutParameterInput{
+			Name:      aws.String(plannedParam.Name),
+			Value:     aws.String(plannedParam.Value),
+			Type:      plannedParam.Type,
+			Overwrite: aws.Bool(plannedParam.Action == PlanUpdate),
+		})
+		if putErr != nil {
+			return plan[:index], global.WrapError(putErr, "global: failed to put parameter %s: %v", plannedParam.Name, putErr)
+		}
+	}
+	return plan, nil
+}
+
+// Plans what to do to every param of the tree, given the params already stored.
+// Returns an error if the name of any param is invalid, so that nothing is put.
+func planParams(
+	configTree *tree.Node,
+	paramPrefix string,
+	existingParams []types.Parameter,
+	overwrite OverwritePolicy,
+) (Plan, global.Error) {
+	existingByName := make(map[string]types.Parameter, len(existingParams))
+	for _, existingParam := range existingParams {
+		existingByName[*existingParam.Name] = existingParam
+	}
+
+	var plan Plan
+	flattenParamTree(configTree, paramPrefix, &plan)
+	sort.Slice(plan, func(i, j int) bool { return plan[i].Name < plan[j].Name })
+
+	var errs []global.Error
+	for _, plannedParam := range plan {
+		if !validParamName.MatchString(plannedParam.Name) {
+			errs = append(errs, &ParamNameError{Name: plannedParam.Name, Kind: ErrInvalidParamName})
+		}
+	}
+	if err := global.JoinErrors(errs...); err != nil {
+		return nil, err
+	}
+
+	for index := range plan {
+		plannedParam := &plan[index]
+		existingParam, exists := existingByName[plannedParam.Name]
+		changed := !exists || aws.ToString(existingParam.Value) != plannedParam.Value ||
+			existingParam.Type != plannedParam.Type
+		switch {
+		case !exists:
+			plannedParam.Action = PlanCreate
+		case overwrite == OverwriteAll || overwrite == OverwriteChanged && changed:
+			plannedParam.Action = PlanUpdate
+		case changed:
+			plannedParam.Action = PlanKeep
+		default:
+			plannedParam.Action = PlanUnchanged
+		}
+	}
+	return plan, nil
+}
+
+// Appends a param for every leaf of the tree, naming it by its path joined with slashes.
+// namePrefix ends with a slash.
+func flattenParamTree(paramTree *tree.Node, namePrefix string, plan *Plan) {
+	if paramTree.Children == nil {
+		name := strings.TrimSuffix(namePrefix, paramStoreSeparator)
+		paramType := types.ParameterTypeString
+		if paramTree.Origin != nil && paramTree.Origin.Type == tree.SecureStringType {
+			paramType = types.ParameterTypeSecureString
+		}
+		*plan = append(*plan, PlannedParam{Name: name, Type: paramType, Value: paramTree.Value})
+		return
+	}
+	for childName, child := range paramTree.Children {
+		flattenParamTree(child, namePrefix+childName+paramStoreSeparator, plan)
+	}
+}
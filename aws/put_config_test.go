@@ -0,0 +1,171 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/railsware/go-global/v2/aws/awstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type putConfigTestConfig struct {
+	Database struct {
+		PoolSize int      `json:"pool_size"`
+		Password string   `json:"password" global:",sensitive"`
+		URLs     []string `json:"urls"`
+	} `json:"database"`
+}
+
+func newPutConfigTestConfig() *putConfigTestConfig {
+	config := &putConfigTestConfig{}
+	config.Database.PoolSize = 20
+	config.Database.Password = "hunter2"
+	config.Database.URLs = []string{"postgres://primary", "postgres://replica"}
+	return config
+}
+
+func TestPutConfigToParameterStore(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeSSM()
+	plan, err := PutConfigToParameterStore(
+		context.Background(),
+		aws.Config{},
+		PutConfigOptions{ParamPrefix: "app", Client: fake},
+		newPutConfigTestConfig(),
+	)
+	require.Nil(t, err)
+	assert.Equal(t, Plan{
+		{Name: "/app/database/password", Type: types.ParameterTypeSecureString, Value: "hunter2", Action: PlanCreate},
+		{Name: "/app/database/pool_size", Type: types.ParameterTypeString, Value: "20", Action: PlanKeep},
+		{Name: "/app/database/urls/0", Type: types.ParameterTypeString, Value: "postgres://primary", Action: PlanUnchanged},
+		{Name: "/app/database/urls/1", Type: types.ParameterTypeString, Value: "postgres://replica", Action: PlanUnchanged},
+	}, plan)
+
+	var config putConfigTestConfig
+	require.Nil(t, LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: fake, IgnoreUnmappedParams: true},
+		&config,
+	))
+	assert.Equal(t, "hunter2", config.Database.Password)
+	assert.Equal(t, 10, config.Database.PoolSize, "existing params are kept")
+
+	plan, err = PutConfigToParameterStore(
+		context.Background(),
+		aws.Config{},
+		PutConfigOptions{ParamPrefix: "app", Client: fake, Overwrite: OverwriteChanged},
+		newPutConfigTestConfig(),
+	)
+	require.Nil(t, err)
+	assert.Equal(t, PlanUnchanged, plan[0].Action)
+	assert.Equal(t, PlanUpdate, plan[1].Action)
+
+	require.Nil(t, LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: fake, IgnoreUnmappedParams: true},
+		&config,
+	))
+	assert.Equal(t, 20, config.Database.PoolSize)
+}
+
+func TestPutConfigToParameterStoreDryRun(t *testing.T) {
+	t.Parallel()
+
+	fake := awstest.NewSSM()
+	fake.Put("/app/database/pool_size", "10")
+
+	var planOutput bytes.Buffer
+	plan, err := PutConfigToParameterStore(
+		context.Background(),
+		aws.Config{},
+		PutConfigOptions{
+			ParamPrefix: "/app/",
+			Client:      fake,
+			Overwrite:   OverwriteAll,
+			DryRun:      true,
+			PlanOutput:  &planOutput,
+		},
+		newPutConfigTestConfig(),
+	)
+	require.Nil(t, err)
+	assert.Len(t, plan, 4)
+	assert.Equal(t, `create /app/database/password (SecureString) = ******
+update /app/database/pool_size (String) = 20
+create /app/database/urls/0 (String) = postgres://primary
+create /app/database/urls/1 (String) = postgres://replica
+`, planOutput.String())
+
+	var config putConfigTestConfig
+	err = LoadConfigFromParameterStore(aws.Config{}, LoadConfigOptions{ParamPrefix: "/app/", Client: fake}, &config)
+	require.Nil(t, err)
+	assert.Equal(t, 10, config.Database.PoolSize, "nothing is put")
+	assert.Empty(t, config.Database.URLs)
+}
+
+func TestPutConfigToParameterStoreWithSensitiveStruct(t *testing.T) {
+	t.Parallel()
+
+	var config struct {
+		Creds struct {
+			User string `json:"user"`
+			Pass string `json:"pass"`
+		} `json:"creds" global:",sensitive"`
+	}
+	config.Creds.User = "u"
+	config.Creds.Pass = "p"
+
+	fake := awstest.NewSSM()
+	var planOutput bytes.Buffer
+	plan, err := PutConfigToParameterStore(
+		context.Background(),
+		aws.Config{},
+		PutConfigOptions{ParamPrefix: "/app/", Client: fake, PlanOutput: &planOutput},
+		&config,
+	)
+	require.Nil(t, err)
+	require.Len(t, plan, 2)
+	assert.Equal(t, types.ParameterTypeSecureString, plan[0].Type)
+	assert.Equal(t, types.ParameterTypeSecureString, plan[1].Type)
+	assert.Equal(t, `create /app/creds/pass (SecureString) = ******
+create /app/creds/user (SecureString) = ******
+`, planOutput.String())
+}
+
+func TestPutConfigToParameterStoreWithInvalidNames(t *testing.T) {
+	t.Parallel()
+
+	config := struct {
+		Limits map[string]int `json:"limits"`
+	}{
+		Limits: map[string]int{"ok": 1, "not ok": 2},
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		fake := awstest.NewSSM()
+		var planOutput bytes.Buffer
+		plan, err := PutConfigToParameterStore(
+			context.Background(),
+			aws.Config{},
+			PutConfigOptions{ParamPrefix: "/app/", Client: fake, DryRun: dryRun, PlanOutput: &planOutput},
+			&config,
+		)
+		require.NotNil(t, err)
+		assert.False(t, err.Warning())
+		assert.Contains(t, err.Error(), "/app/limits/not ok")
+		assert.NotContains(t, err.Error(), "/app/limits/ok")
+		assert.Nil(t, plan)
+		assert.Empty(t, planOutput.String())
+
+		var loaded struct {
+			Limits map[string]int `json:"limits"`
+		}
+		err = LoadConfigFromParameterStore(aws.Config{}, LoadConfigOptions{ParamPrefix: "/app/", Client: fake}, &loaded)
+		require.Nil(t, err)
+		assert.Empty(t, loaded.Limits, "nothing is put")
+	}
+}
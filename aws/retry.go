@@ -0,0 +1,116 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/aws/smithy-go"
+)
+
+// RetryPolicy retries failed requests of a page of params, with exponential backoff.
+// It applies on top of the retries of the AWS client itself.
+type RetryPolicy struct {
+	// MaxAttempts of each request, including the first one. If zero or one, requests are not retried.
+	MaxAttempts int
+	// BaseDelay before the first retry, doubled for every next one. Defaults to 100ms.
+	BaseDelay time.Duration
+	// MaxDelay limits the delay before a retry. If zero, the delay is not limited.
+	MaxDelay time.Duration
+	// Jitter is the fraction of each delay, from 0 to 1, that is randomised
+	// so that many clients starting at once don't retry in lockstep.
+	Jitter float64
+	// Retryable reports whether a failed request should be retried. Defaults to IsRetryableError.
+	Retryable func(err error) bool
+}
+
+const defaultRetryBaseDelay = 100 * time.Millisecond
+
+// Error codes of AWS APIs meaning the request may succeed if retried later.
+var retryableErrorCodes = map[string]bool{
+	"ThrottlingException":                    true,
+	"Throttling":                             true,
+	"TooManyRequestsException":               true,
+	"RequestLimitExceeded":                   true,
+	"ProvisionedThroughputExceededException": true,
+	"InternalServerError":                    true,
+	"ServiceUnavailable":                     true,
+}
+
+// IsRetryableError reports whether err is an AWS API error caused by throttling or a server fault.
+func IsRetryableError(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return retryableErrorCodes[apiErr.ErrorCode()] || apiErr.ErrorFault() == smithy.FaultServer
+}
+
+// Returns the delay before the given retry, counting from 1.
+func (policy RetryPolicy) delay(retry int) time.Duration {
+	delay := policy.BaseDelay
+	if delay <= 0 {
+		delay = defaultRetryBaseDelay
+	}
+	for i := 1; i < retry && (policy.MaxDelay <= 0 || delay < policy.MaxDelay); i++ {
+		delay *= 2
+	}
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	if policy.Jitter > 0 {
+		jitter := policy.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay)) //nolint:gosec // no need for secure randomness
+	}
+	return delay
+}
+
+func (policy RetryPolicy) retryable(err error) bool {
+	if policy.Retryable != nil {
+		return policy.Retryable(err)
+	}
+	return IsRetryableError(err)
+}
+
+// Calls request until it succeeds, fails with an error that isn't retryable, attempts run out or ctx is done.
+// The error after several attempts unwraps to the error of the last one.
+func retry[T any](ctx context.Context, policy RetryPolicy, request func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := request()
+		if err == nil || ctx.Err() != nil {
+			return result, err
+		}
+		if attempt >= policy.MaxAttempts || !policy.retryable(err) {
+			if attempt > 1 {
+				err = &retriedError{err: err, attempts: attempt}
+			}
+			return result, err
+		}
+
+		timer := time.NewTimer(policy.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, &retriedError{err: err, attempts: attempt}
+		case <-timer.C:
+		}
+	}
+}
+
+type retriedError struct {
+	err      error
+	attempts int
+}
+
+func (e *retriedError) Error() string {
+	return fmt.Sprintf("%v (after %d attempts)", e.err, e.attempts)
+}
+
+func (e *retriedError) Unwrap() error {
+	return e.err
+}
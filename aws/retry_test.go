@@ -0,0 +1,173 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errThrottling = &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+
+// Fails the first requests of every page with err, recording the tokens requested.
+type flakySSMClient struct {
+	SSMClient
+	failuresPerPage int
+	err             error
+
+	mutex    sync.Mutex
+	failures map[string]int
+	tokens   []string
+}
+
+func (c *flakySSMClient) GetParametersByPath(
+	ctx context.Context,
+	params *ssm.GetParametersByPathInput,
+	optFns ...func(*ssm.Options),
+) (*ssm.GetParametersByPathOutput, error) {
+	c.mutex.Lock()
+	token := aws.ToString(params.NextToken)
+	c.tokens = append(c.tokens, token)
+	if c.failures == nil {
+		c.failures = make(map[string]int)
+	}
+	c.failures[token]++
+	failed := c.failures[token] <= c.failuresPerPage
+	c.mutex.Unlock()
+
+	if failed {
+		return nil, c.err
+	}
+	return c.SSMClient.GetParametersByPath(ctx, params, optFns...)
+}
+
+func TestLoadConfigRetriesFailedPage(t *testing.T) {
+	t.Parallel()
+
+	client := &flakySSMClient{SSMClient: newFakeSSM(), failuresPerPage: 2, err: errThrottling}
+
+	var config paramStoreConfig
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{
+			ParamPrefix:          "/app/",
+			Client:               client,
+			IgnoreUnmappedParams: true,
+			Retry:                RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+		},
+		&config,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, 10, config.Database.PoolSize)
+	assert.Equal(t, []string{"", "", "", "2", "2", "2"}, client.tokens, "resumes from the failed page")
+}
+
+func TestLoadConfigGivesUpRetrying(t *testing.T) {
+	t.Parallel()
+
+	client := &flakySSMClient{SSMClient: newFakeSSM(), failuresPerPage: 3, err: errThrottling}
+
+	var config paramStoreConfig
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{
+			ParamPrefix: "/app/",
+			Client:      client,
+			Retry:       RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+		},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.ErrorIs(t, err, errThrottling)
+	assert.Contains(t, err.Error(), "Rate exceeded (after 3 attempts)")
+	assert.Len(t, client.tokens, 3)
+}
+
+func TestLoadConfigDoesNotRetryOtherErrors(t *testing.T) {
+	t.Parallel()
+
+	client := &flakySSMClient{SSMClient: newFakeSSM(), failuresPerPage: 1, err: errSSMUnavailable}
+
+	var config paramStoreConfig
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: client, Retry: RetryPolicy{MaxAttempts: 3}},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.ErrorIs(t, err, errSSMUnavailable)
+	assert.NotContains(t, err.Error(), "attempts")
+	assert.Len(t, client.tokens, 1)
+
+	client = &flakySSMClient{SSMClient: newFakeSSM(), failuresPerPage: 1, err: errSSMUnavailable}
+	err = LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{
+			ParamPrefix:          "/app/",
+			Client:               client,
+			IgnoreUnmappedParams: true,
+			Retry: RetryPolicy{
+				MaxAttempts: 2,
+				BaseDelay:   time.Millisecond,
+				Retryable:   func(err error) bool { return errors.Is(err, errSSMUnavailable) },
+			},
+		},
+		&config,
+	)
+	require.Nil(t, err, "retried with a custom classifier")
+}
+
+func TestLoadConfigStopsRetryingWhenCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	client := &flakySSMClient{SSMClient: newFakeSSM(), failuresPerPage: 1, err: errThrottling}
+
+	var config paramStoreConfig
+	err := LoadConfigFromParameterStoreWithContext(
+		ctx,
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: client, Retry: RetryPolicy{MaxAttempts: 2, BaseDelay: time.Hour}},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, client.tokens, 1)
+}
+
+func TestIsRetryableError(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsRetryableError(errThrottling))
+	assert.True(t, IsRetryableError(&smithy.GenericAPIError{Code: "Unknown", Fault: smithy.FaultServer}))
+	assert.False(t, IsRetryableError(&smithy.GenericAPIError{Code: "ValidationException"}))
+	assert.False(t, IsRetryableError(errSSMUnavailable))
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	assert.Equal(t, time.Second, policy.delay(1))
+	assert.Equal(t, 2*time.Second, policy.delay(2))
+	assert.Equal(t, 4*time.Second, policy.delay(3))
+	assert.Equal(t, 5*time.Second, policy.delay(4))
+	assert.Equal(t, 5*time.Second, policy.delay(100))
+
+	assert.Equal(t, defaultRetryBaseDelay, RetryPolicy{}.delay(1))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := policy.delay(2)
+		assert.True(t, delay > time.Second && delay <= 2*time.Second, "delay %v", delay)
+	}
+}
@@ -0,0 +1,166 @@
+package aws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/tree"
+	"github.com/railsware/go-global/v2/utils"
+)
+
+type SecretsManagerOptions struct {
+	// NamePrefix selects the secrets whose names start with it, e.g. "billing/prod/".
+	// The rest of the name, split by slashes, is the path of the secret in the config.
+	NamePrefix string
+	// SecretIDs are names or ARNs of secrets to load in addition to those under NamePrefix.
+	// If set, secrets are only listed when NamePrefix is set too.
+	SecretIDs []string
+	// Client is used to access Secrets Manager. If not set, a client is created from the AWS config.
+	Client SecretsManagerClient
+	// If IgnoreUnmappedParams is set, a secret with no matching config field will be silently ignored.
+	IgnoreUnmappedParams bool
+	// If RequireAllParams is set, every config field must be supplied by a secret or a default.
+	// Otherwise, only fields tagged `global:",required"` must be.
+	RequireAllParams bool
+	// Provenance, if not nil, collects the origins of the loaded values, see tree.WriteOptions.
+	Provenance global.Provenance
+}
+
+func (options SecretsManagerOptions) writeOptions() tree.WriteOptions {
+	return tree.WriteOptions{
+		RequireAll:           options.RequireAllParams,
+		ParamPrefix:          options.NamePrefix,
+		IgnoreUnmappedParams: options.IgnoreUnmappedParams,
+		Provenance:           options.Provenance,
+	}
+}
+
+// LoadConfigFromSecretsManager retrieves secrets and writes them to config.
+//   - config must be a pointer to a struct.
+//   - A secret whose value is a JSON object is expanded into nested fields, any other value is a single field.
+//   - Secret names are split with slashes and matched to struct fields like Parameter Store keys.
+func LoadConfigFromSecretsManager(
+	awsConfig aws.Config,
+	options SecretsManagerOptions,
+	globalConfig interface{},
+) global.Error {
+	return LoadConfigFromSecretsManagerWithContext(context.Background(), awsConfig, options, globalConfig)
+}
+
+// LoadConfigFromSecretsManagerWithContext is LoadConfigFromSecretsManager that stops loading when ctx is done.
+func LoadConfigFromSecretsManagerWithContext( //nolint:nonamedreturns // false positive, using named return for defer
+	ctx context.Context,
+	awsConfig aws.Config,
+	options SecretsManagerOptions,
+	globalConfig interface{},
+) (err global.Error) {
+	defer func() {
+		if panicErr := recover(); panicErr != nil {
+			err = global.NewError("global: panic while loading from secrets manager: %v", panicErr)
+			return
+		}
+	}()
+
+	if _, err := utils.ReflectConfig(globalConfig); err != nil {
+		return err
+	}
+
+	secretTree, err := loadSecretTree(ctx, awsConfig, options)
+	if err != nil {
+		return err
+	}
+
+	return secretTree.WriteConfig(globalConfig, options.writeOptions())
+}
+
+// SecretsManagerSource returns a source of the secret tree, to be combined with other sources.
+// options.IgnoreUnmappedParams has no effect here.
+func SecretsManagerSource(awsConfig aws.Config, options SecretsManagerOptions) tree.Source {
+	return func() (*tree.Node, global.Error) {
+		return loadSecretTree(context.Background(), awsConfig, options)
+	}
+}
+
+func loadSecretTree(
+	ctx context.Context,
+	awsConfig aws.Config,
+	options SecretsManagerOptions,
+) (*tree.Node, global.Error) {
+	client := options.Client
+	if client == nil {
+		client = secretsmanager.NewFromConfig(awsConfig)
+	}
+
+	secretIDs := options.SecretIDs
+	if options.NamePrefix != "" || len(options.SecretIDs) == 0 {
+		listedIDs, err := listSecrets(ctx, client, options.NamePrefix)
+		if err != nil {
+			return nil, err
+		}
+		secretIDs = append(listedIDs, secretIDs...)
+	}
+
+	var secrets []secret
+	for _, secretID := range secretIDs {
+		output, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(secretID)})
+		if err != nil {
+			return nil, wrapLoadError(ctx, err, "Secrets Manager")
+		}
+		secrets = append(secrets, newSecret(output, options.NamePrefix))
+	}
+
+	return buildSecretTree(secrets), nil
+}
+
+// Returns names of the secrets starting with namePrefix.
+func listSecrets(ctx context.Context, client SecretsManagerClient, namePrefix string) ([]string, global.Error) {
+	input := &secretsmanager.ListSecretsInput{}
+	if namePrefix != "" {
+		input.Filters = []types.Filter{{Key: types.FilterNameStringTypeName, Values: []string{namePrefix}}}
+	}
+	secretPaginator := secretsmanager.NewListSecretsPaginator(client, input)
+
+	var names []string
+	for secretPaginator.HasMorePages() {
+		page, err := secretPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, wrapLoadError(ctx, err, "Secrets Manager")
+		}
+		for _, entry := range page.SecretList {
+			// The name filter is case-insensitive, so it may return secrets that don't match the prefix exactly.
+			if strings.HasPrefix(aws.ToString(entry.Name), namePrefix) {
+				names = append(names, aws.ToString(entry.Name))
+			}
+		}
+	}
+	return names, nil
+}
+
+type secret struct {
+	path   string
+	value  string
+	origin *global.Origin
+}
+
+func newSecret(output *secretsmanager.GetSecretValueOutput, namePrefix string) secret {
+	name := aws.ToString(output.Name)
+	value := aws.ToString(output.SecretString)
+	if output.SecretString == nil {
+		value = string(output.SecretBinary)
+	}
+	return secret{
+		path:  strings.TrimPrefix(name, namePrefix),
+		value: value,
+		origin: &global.Origin{
+			Backend:      global.BackendSecretsManager,
+			Name:         name,
+			Prefix:       namePrefix,
+			Version:      aws.ToString(output.VersionId),
+			LastModified: aws.ToTime(output.CreatedDate),
+		},
+	}
+}
@@ -0,0 +1,22 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// SecretsManagerClient is the part of the Secrets Manager API used by the loader.
+// *secretsmanager.Client implements it.
+type SecretsManagerClient interface {
+	ListSecrets(
+		ctx context.Context,
+		params *secretsmanager.ListSecretsInput,
+		optFns ...func(*secretsmanager.Options),
+	) (*secretsmanager.ListSecretsOutput, error)
+	GetSecretValue(
+		ctx context.Context,
+		params *secretsmanager.GetSecretValueInput,
+		optFns ...func(*secretsmanager.Options),
+	) (*secretsmanager.GetSecretValueOutput, error)
+}
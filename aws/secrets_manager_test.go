@@ -0,0 +1,129 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/railsware/go-global/v2/aws/awstest"
+	"github.com/railsware/go-global/v2/tree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type secretsConfig struct {
+	Database struct {
+		User     string   `json:"user"`
+		Password string   `json:"password"`
+		Port     int      `json:"port"`
+		Replicas []string `json:"replicas"`
+	} `json:"database"`
+	APIKey string `json:"api_key"`
+}
+
+func newFakeSecretsManager() *awstest.SecretsManager {
+	fake := awstest.NewSecretsManager()
+	fake.PageSize = 1
+	fake.Put("billing/prod/database", `{"user": "billing", "password": "secret", "port": 5432, "replicas": ["r1", "r2"]}`)
+	fake.Put("billing/prod/api_key", "key")
+	fake.Put("billing/staging/api_key", "staging-key")
+	fake.Put("shared/api_key", "shared-key")
+	return fake
+}
+
+func TestLoadConfigFromSecretsManager(t *testing.T) {
+	t.Parallel()
+
+	var config secretsConfig
+	err := LoadConfigFromSecretsManager(
+		aws.Config{},
+		SecretsManagerOptions{NamePrefix: "billing/prod/", Client: newFakeSecretsManager()},
+		&config,
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "billing", config.Database.User)
+	assert.Equal(t, "secret", config.Database.Password)
+	assert.Equal(t, 5432, config.Database.Port)
+	assert.Equal(t, []string{"r1", "r2"}, config.Database.Replicas)
+	assert.Equal(t, "key", config.APIKey)
+}
+
+func TestLoadConfigFromSecretsManagerByID(t *testing.T) {
+	t.Parallel()
+
+	var config struct {
+		Shared struct {
+			APIKey string `json:"api_key"`
+		} `json:"shared"`
+	}
+	err := LoadConfigFromSecretsManager(
+		aws.Config{},
+		SecretsManagerOptions{SecretIDs: []string{"shared/api_key"}, Client: newFakeSecretsManager()},
+		&config,
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "shared-key", config.Shared.APIKey)
+
+	err = LoadConfigFromSecretsManager(
+		aws.Config{},
+		SecretsManagerOptions{SecretIDs: []string{"unknown"}, Client: newFakeSecretsManager()},
+		&config,
+	)
+	require.NotNil(t, err)
+	var notFound *types.ResourceNotFoundException
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestLoadConfigFromSecretsManagerReportsBadValues(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeSecretsManager()
+	fake.Put("billing/prod/database", `{"port": "not a number"}`)
+
+	var config secretsConfig
+	err := LoadConfigFromSecretsManager(
+		aws.Config{},
+		SecretsManagerOptions{NamePrefix: "billing/prod/", Client: fake},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.ErrorIs(t, err, tree.ErrParse)
+	assert.Contains(t, err.Error(), "database/port: cannot read int param value")
+	assert.Contains(t, err.Error(), "(from billing/prod/database)")
+}
+
+func TestLoadConfigFromSecretsManagerCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var config secretsConfig
+	err := LoadConfigFromSecretsManagerWithContext(
+		ctx,
+		aws.Config{},
+		SecretsManagerOptions{NamePrefix: "billing/prod/", Client: newFakeSecretsManager()},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestLoadConfigFromSecretsManagerRecoversPanics(t *testing.T) {
+	t.Parallel()
+
+	// calls to the embedded nil client panic
+	type panickingClient struct {
+		SecretsManagerClient
+	}
+
+	var config secretsConfig
+	err := LoadConfigFromSecretsManager(
+		aws.Config{},
+		SecretsManagerOptions{NamePrefix: "billing/prod/", Client: panickingClient{}},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "global: panic while loading from secrets manager")
+}
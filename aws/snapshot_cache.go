@@ -0,0 +1,197 @@
+package aws
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/railsware/go-global/v2"
+)
+
+const snapshotFileMode = 0o600
+
+var (
+	errSnapshotTooShort      = errors.New("snapshot is too short")
+	errSnapshotOtherPrefixes = errors.New("snapshot was saved for other prefixes")
+	errSnapshotOtherOptions  = errors.New("snapshot was saved with other FetchByName or Label options")
+	errSnapshotFromTheFuture = errors.New("snapshot was saved in the future")
+)
+
+// SnapshotCache keeps the params of the last successful load in a file, so that the config can still be loaded
+// when Parameter Store is unavailable, e.g. throttled during a deploy.
+// The file is encrypted, as it holds decrypted SecureString values.
+type SnapshotCache struct {
+	// Path of the snapshot file. Its directory must exist.
+	Path string
+	// Key to encrypt the snapshot with AES-GCM. Must be 16, 24 or 32 bytes long.
+	Key []byte
+	// MaxAge of a snapshot to fall back to. If zero, a snapshot of any age is used.
+	MaxAge time.Duration
+}
+
+type snapshot struct {
+	SavedAt time.Time `json:"saved_at"`
+	snapshotKey
+	// Params of each mount.
+	Params [][]snapshotParam `json:"params"`
+}
+
+// snapshotKey holds the options that decide which params are fetched,
+// to check that the snapshot was saved with the same options.
+type snapshotKey struct {
+	// Mounts are normalised.
+	Mounts      []PrefixMount `json:"mounts"`
+	FetchByName bool          `json:"fetch_by_name,omitempty"`
+	Label       string        `json:"label,omitempty"`
+}
+
+// Returns the key of a snapshot of params fetched for the mounts with the options.
+func newSnapshotKey(mounts []PrefixMount, options LoadConfigOptions) snapshotKey {
+	return snapshotKey{Mounts: normalizeMounts(mounts), FetchByName: options.FetchByName, Label: options.Label}
+}
+
+type snapshotParam struct {
+	Path   string         `json:"path"`
+	Value  string         `json:"value"`
+	Origin *global.Origin `json:"origin"`
+}
+
+// Saves the params fetched for each mount of the key.
+func (cache *SnapshotCache) save(key snapshotKey, paramsByMount [][]param, now time.Time) error {
+	saved := snapshot{SavedAt: now, snapshotKey: key, Params: make([][]snapshotParam, 0, len(paramsByMount))}
+	for _, params := range paramsByMount {
+		snapshotParams := make([]snapshotParam, 0, len(params))
+		for _, param := range params {
+			snapshotParams = append(snapshotParams, snapshotParam{param.path, param.value, param.origin})
+		}
+		saved.Params = append(saved.Params, snapshotParams)
+	}
+
+	plaintext, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := cache.seal(plaintext)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so that a crash doesn't leave a broken snapshot
+	file, err := os.CreateTemp(filepath.Dir(cache.Path), filepath.Base(cache.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(ciphertext); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), snapshotFileMode); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), cache.Path)
+}
+
+// Loads the params of each mount from a snapshot saved with the same key, if it is not older than MaxAge.
+func (cache *SnapshotCache) load(key snapshotKey, now time.Time) ([][]param, time.Time, error) {
+	ciphertext, err := os.ReadFile(cache.Path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	plaintext, err := cache.open(ciphertext)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var saved snapshot
+	if err := json.Unmarshal(plaintext, &saved); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	age := now.Sub(saved.SavedAt)
+	switch {
+	case !equalMounts(saved.Mounts, key.Mounts) || len(saved.Params) != len(key.Mounts):
+		return nil, time.Time{}, errSnapshotOtherPrefixes
+	case saved.FetchByName != key.FetchByName || saved.Label != key.Label:
+		return nil, time.Time{}, errSnapshotOtherOptions
+	case age < 0:
+		return nil, time.Time{}, errSnapshotFromTheFuture
+	case cache.MaxAge > 0 && age > cache.MaxAge:
+		return nil, time.Time{}, fmt.Errorf("snapshot is %v old, older than %v", age.Round(time.Second), cache.MaxAge)
+	}
+
+	paramsByMount := make([][]param, 0, len(saved.Params))
+	for _, snapshotParams := range saved.Params {
+		params := make([]param, 0, len(snapshotParams))
+		for _, snapshotParam := range snapshotParams {
+			params = append(params, param{snapshotParam.Path, snapshotParam.Value, snapshotParam.Origin})
+		}
+		paramsByMount = append(paramsByMount, params)
+	}
+	return paramsByMount, saved.SavedAt, nil
+}
+
+// Encrypts the plaintext, prepending the nonce.
+func (cache *SnapshotCache) seal(plaintext []byte) ([]byte, error) {
+	aead, err := cache.aead()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypts what seal has encrypted.
+func (cache *SnapshotCache) open(ciphertext []byte) ([]byte, error) {
+	aead, err := cache.aead()
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errSnapshotTooShort
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, nil)
+}
+
+func (cache *SnapshotCache) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(cache.Key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func normalizeMounts(mounts []PrefixMount) []PrefixMount {
+	normalized := make([]PrefixMount, 0, len(mounts))
+	for _, mount := range mounts {
+		normalized = append(normalized, PrefixMount{
+			ParamPrefix: normalizeParamPrefix(mount.ParamPrefix),
+			MountPath:   mountPathPrefix(mount.MountPath),
+		})
+	}
+	return normalized
+}
+
+func equalMounts(mounts []PrefixMount, otherMounts []PrefixMount) bool {
+	if len(mounts) != len(otherMounts) {
+		return false
+	}
+	for index := range mounts {
+		if mounts[index] != otherMounts[index] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/railsware/go-global/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errSSMUnavailable = errors.New("ssm is unavailable")
+
+type failingSSMClient struct{}
+
+func (failingSSMClient) GetParametersByPath(
+	context.Context,
+	*ssm.GetParametersByPathInput,
+	...func(*ssm.Options),
+) (*ssm.GetParametersByPathOutput, error) {
+	return nil, errSSMUnavailable
+}
+
+func newSnapshotCache(t *testing.T) *SnapshotCache {
+	t.Helper()
+	return &SnapshotCache{
+		Path:   filepath.Join(t.TempDir(), "snapshot"),
+		Key:    []byte("0123456789abcdef0123456789abcdef"),
+		MaxAge: time.Hour,
+	}
+}
+
+func TestLoadConfigFallsBackToSnapshot(t *testing.T) {
+	t.Parallel()
+
+	cache := newSnapshotCache(t)
+
+	var config paramStoreConfig
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: newFakeSSM(), IgnoreUnmappedParams: true, SnapshotCache: cache},
+		&config,
+	)
+	require.Nil(t, err)
+
+	info, statErr := os.Stat(cache.Path)
+	require.NoError(t, statErr)
+	assert.Equal(t, os.FileMode(snapshotFileMode), info.Mode().Perm())
+	contents, readErr := os.ReadFile(cache.Path)
+	require.NoError(t, readErr)
+	assert.NotContains(t, string(contents), "postgres://primary", "snapshot is encrypted")
+
+	var cachedConfig paramStoreConfig
+	provenance := make(global.Provenance)
+	err = LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{
+			ParamPrefix:          "/app/",
+			Client:               failingSSMClient{},
+			IgnoreUnmappedParams: true,
+			SnapshotCache:        cache,
+			Provenance:           provenance,
+		},
+		&cachedConfig,
+	)
+	require.NotNil(t, err)
+	assert.True(t, err.Warning())
+	assert.ErrorIs(t, err, errSSMUnavailable)
+	assert.Contains(t, err.Error(), "using snapshot saved at")
+	assert.Equal(t, config, cachedConfig)
+
+	origin, ok := provenance["Database.PoolSize"]
+	require.True(t, ok)
+	assert.Equal(t, "/app/database/pool_size", origin.Name)
+
+	err = LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/other/", Client: failingSSMClient{}, SnapshotCache: cache},
+		&cachedConfig,
+	)
+	require.NotNil(t, err)
+	assert.False(t, err.Warning())
+	assert.ErrorIs(t, err, errSSMUnavailable)
+	assert.ErrorIs(t, err, errSnapshotOtherPrefixes)
+
+	err = LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: failingSSMClient{}, FetchByName: true, SnapshotCache: cache},
+		&cachedConfig,
+	)
+	require.NotNil(t, err)
+	assert.False(t, err.Warning())
+	assert.ErrorIs(t, err, errSnapshotOtherOptions, "the snapshot holds all params under the prefix")
+}
+
+func TestLoadConfigWithoutSnapshot(t *testing.T) {
+	t.Parallel()
+
+	var config paramStoreConfig
+	err := LoadConfigFromParameterStore(
+		aws.Config{},
+		LoadConfigOptions{ParamPrefix: "/app/", Client: failingSSMClient{}, SnapshotCache: newSnapshotCache(t)},
+		&config,
+	)
+	require.NotNil(t, err)
+	assert.False(t, err.Warning())
+	assert.ErrorIs(t, err, errSSMUnavailable)
+}
+
+func TestSnapshotCache(t *testing.T) {
+	t.Parallel()
+
+	cache := newSnapshotCache(t)
+	mounts := []PrefixMount{{ParamPrefix: "app"}, {ParamPrefix: "/shared/", MountPath: "shared"}}
+	paramsByMount := [][]param{
+		{{path: "a", value: "1", origin: &global.Origin{Name: "/app/a"}}},
+		{},
+	}
+	savedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	options := LoadConfigOptions{FetchByName: true, Label: "stable"}
+	key := newSnapshotKey(mounts, options)
+	require.NoError(t, cache.save(key, paramsByMount, savedAt))
+
+	loadedParams, loadedAt, err := cache.load(
+		newSnapshotKey(
+			[]PrefixMount{{ParamPrefix: "/app/"}, {ParamPrefix: "shared", MountPath: "/shared/"}},
+			options,
+		),
+		savedAt.Add(time.Minute),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, paramsByMount, loadedParams)
+	assert.True(t, savedAt.Equal(loadedAt))
+
+	_, _, err = cache.load(key, savedAt.Add(2*time.Hour))
+	assert.ErrorContains(t, err, "older than 1h0m0s")
+
+	_, _, err = cache.load(key, savedAt.Add(-time.Hour))
+	assert.ErrorIs(t, err, errSnapshotFromTheFuture)
+
+	_, _, err = cache.load(newSnapshotKey(mounts[:1], options), savedAt)
+	assert.ErrorIs(t, err, errSnapshotOtherPrefixes)
+
+	_, _, err = cache.load(newSnapshotKey(mounts, LoadConfigOptions{Label: "stable"}), savedAt)
+	assert.ErrorIs(t, err, errSnapshotOtherOptions)
+
+	_, _, err = cache.load(newSnapshotKey(mounts, LoadConfigOptions{FetchByName: true, Label: "beta"}), savedAt)
+	assert.ErrorIs(t, err, errSnapshotOtherOptions)
+
+	otherKeyCache := *cache
+	otherKeyCache.Key = []byte("fedcba9876543210fedcba9876543210")
+	_, _, err = otherKeyCache.load(key, savedAt)
+	assert.Error(t, err)
+}
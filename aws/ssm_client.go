@@ -0,0 +1,36 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// SSMClient is the part of the Parameter Store API used by the loader. *ssm.Client implements it.
+type SSMClient interface {
+	GetParametersByPath(
+		ctx context.Context,
+		params *ssm.GetParametersByPathInput,
+		optFns ...func(*ssm.Options),
+	) (*ssm.GetParametersByPathOutput, error)
+}
+
+// SSMGetClient is the part of the Parameter Store API used to load params by name. *ssm.Client implements it.
+type SSMGetClient interface {
+	SSMClient
+	GetParameters(
+		ctx context.Context,
+		params *ssm.GetParametersInput,
+		optFns ...func(*ssm.Options),
+	) (*ssm.GetParametersOutput, error)
+}
+
+// SSMPutClient is the part of the Parameter Store API used to put config params. *ssm.Client implements it.
+type SSMPutClient interface {
+	SSMClient
+	PutParameter(
+		ctx context.Context,
+		params *ssm.PutParameterInput,
+		optFns ...func(*ssm.Options),
+	) (*ssm.PutParameterOutput, error)
+}
@@ -0,0 +1,169 @@
+package aws
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/tree"
+)
+
+const defaultWatchInterval = time.Minute
+
+type WatchOptions struct {
+	// LoadConfigOptions.Provenance is not used, every config gets its own, see Watcher.LoadWithProvenance.
+	LoadConfigOptions
+	// Interval between fetches from Parameter Store. Defaults to one minute.
+	Interval time.Duration
+	// OnError is called when a reload fails. The previous config stays in place.
+	OnError func(err global.Error)
+}
+
+// Watcher keeps a config loaded from Parameter Store up to date.
+// Use Load to read the current config and Subscribe to get notified about changes.
+type Watcher[T any] struct {
+	*global.Holder[T]
+
+	options WatchOptions
+	fetch   func(ctx context.Context) (*tree.Node, global.Error)
+
+	// mutex guards paramTree and serializes reloads
+	mutex     sync.Mutex
+	paramTree *tree.Node
+
+	// explained is the last stored config with the origins of its values
+	explained atomic.Pointer[explainedConfig[T]]
+}
+
+type explainedConfig[T any] struct {
+	config     *T
+	provenance global.Provenance
+}
+
+// NewWatcher loads the config like LoadConfigFromParameterStore and returns a watcher to keep it up to date.
+//   - T must be a struct type.
+//   - Every reload writes into a fresh zero value of T, which then replaces the current config.
+//   - Like Reload, returns warnings along with the watcher.
+//   - The initial load stops when ctx is done, see LoadConfigFromParameterStoreWithContext.
+func NewWatcher[T any](ctx context.Context, awsConfig aws.Config, options WatchOptions) (*Watcher[T], global.Error) {
+	return newWatcher[T](
+		ctx,
+		func(ctx context.Context) (*tree.Node, global.Error) {
+			return loadParamTree(ctx, awsConfig, options.LoadConfigOptions, new(T))
+		},
+		options,
+	)
+}
+
+func newWatcher[T any](
+	ctx context.Context,
+	fetch func(ctx context.Context) (*tree.Node, global.Error),
+	options WatchOptions,
+) (*Watcher[T], global.Error) {
+	paramTree, err := fetch(ctx)
+	if err != nil && !err.Warning() {
+		return nil, err
+	}
+
+	config := new(T)
+	provenance, writeErr := writeWatchedConfig(paramTree, config, options)
+	writeErr = global.JoinErrors(err, writeErr)
+	if writeErr != nil && !writeErr.Warning() {
+		return nil, writeErr
+	}
+
+	watcher := &Watcher[T]{
+		Holder:    global.NewHolderWithConfig(config),
+		options:   options,
+		fetch:     fetch,
+		paramTree: paramTree,
+	}
+	watcher.explained.Store(&explainedConfig[T]{config: config, provenance: provenance})
+	return watcher, writeErr
+}
+
+// Writes the tree into config, collecting the origins of its values.
+func writeWatchedConfig[T any](
+	paramTree *tree.Node,
+	config *T,
+	options WatchOptions,
+) (global.Provenance, global.Error) {
+	writeOptions := options.writeOptions()
+	writeOptions.Provenance = make(global.Provenance)
+	err := paramTree.WriteConfig(config, writeOptions)
+	return writeOptions.Provenance, err
+}
+
+// LoadWithProvenance returns the current config along with the origins of its values.
+// The provenance is nil if the config was set with Store.
+func (watcher *Watcher[T]) LoadWithProvenance() (*T, global.Provenance) {
+	explained := watcher.explained.Load()
+	return explained.config, explained.provenance
+}
+
+// Store replaces the config and notifies subscribers, see global.Holder.Store.
+func (watcher *Watcher[T]) Store(newConfig *T) {
+	watcher.store(newConfig, nil)
+}
+
+func (watcher *Watcher[T]) store(newConfig *T, provenance global.Provenance) {
+	watcher.explained.Store(&explainedConfig[T]{config: newConfig, provenance: provenance})
+	watcher.Holder.Store(newConfig)
+}
+
+// Reload fetches the parameters and, if anything has changed, replaces the config and notifies subscribers.
+// Warnings are returned, but don't prevent the replacement. On errors, the current config is kept.
+func (watcher *Watcher[T]) Reload() global.Error {
+	return watcher.reload(context.Background())
+}
+
+func (watcher *Watcher[T]) reload(ctx context.Context) global.Error {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+
+	paramTree, err := watcher.fetch(ctx)
+	if err != nil && !err.Warning() {
+		return err
+	}
+	if paramTree.Equal(watcher.paramTree) {
+		return err
+	}
+
+	newConfig := new(T)
+	provenance, writeErr := writeWatchedConfig(paramTree, newConfig, watcher.options)
+	writeErr = global.JoinErrors(err, writeErr)
+	if writeErr != nil && !writeErr.Warning() {
+		return writeErr
+	}
+
+	watcher.paramTree = paramTree
+	watcher.store(newConfig, provenance)
+
+	return writeErr
+}
+
+// Run reloads the config every options.Interval until ctx is done, which also cancels a reload in progress.
+// Errors are passed to options.OnError.
+func (watcher *Watcher[T]) Run(ctx context.Context) {
+	interval := watcher.options.Interval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := watcher.reload(ctx); err != nil && ctx.Err() == nil && watcher.options.OnError != nil {
+				watcher.options.OnError(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/tree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type watchedConfig struct {
+	Host     string `json:"host"`
+	PoolSize int    `json:"pool_size"`
+}
+
+// Returns a fetch function that returns the trees one by one, repeating the last one.
+func sequentialFetch(trees ...*tree.Node) func(context.Context) (*tree.Node, global.Error) {
+	return func(context.Context) (*tree.Node, global.Error) {
+		paramTree := trees[0]
+		if len(trees) > 1 {
+			trees = trees[1:]
+		}
+		return paramTree, nil
+	}
+}
+
+func TestWatcherReload(t *testing.T) {
+	t.Parallel()
+
+	initialTree := &tree.Node{Children: map[string]*tree.Node{
+		"host":      {Value: "localhost"},
+		"pool_size": {Value: "5"},
+	}}
+	sameTree := &tree.Node{Children: map[string]*tree.Node{
+		"host":      {Value: "localhost"},
+		"pool_size": {Value: "5"},
+	}}
+	brokenTree := &tree.Node{Children: map[string]*tree.Node{
+		"pool_size": {Value: "broken"},
+	}}
+	updatedTree := &tree.Node{Children: map[string]*tree.Node{
+		"pool_size": {Value: "10"},
+	}}
+
+	watcher, err := newWatcher[watchedConfig](
+		context.Background(),
+		sequentialFetch(initialTree, sameTree, brokenTree, updatedTree),
+		WatchOptions{},
+	)
+	require.Nil(t, err)
+	config := watcher.Load()
+	assert.Equal(t, &watchedConfig{Host: "localhost", PoolSize: 5}, config)
+
+	var changes [][2]watchedConfig
+	watcher.Subscribe(func(oldConfig, newConfig *watchedConfig) {
+		changes = append(changes, [2]watchedConfig{*oldConfig, *newConfig})
+	})
+
+	require.Nil(t, watcher.Reload(), "same tree")
+	assert.Same(t, config, watcher.Load())
+	assert.Empty(t, changes)
+
+	require.NotNil(t, watcher.Reload(), "broken tree")
+	assert.Same(t, config, watcher.Load())
+	assert.Empty(t, changes)
+
+	require.Nil(t, watcher.Reload(), "updated tree")
+	assert.Equal(t, &watchedConfig{PoolSize: 10}, watcher.Load(), "writes into a fresh struct")
+	assert.Equal(t, &watchedConfig{Host: "localhost", PoolSize: 5}, config, "old config is left intact")
+	assert.Equal(t, [][2]watchedConfig{{*config, {PoolSize: 10}}}, changes)
+}
+
+func TestWatcherRun(t *testing.T) {
+	t.Parallel()
+
+	initialTree := &tree.Node{Children: map[string]*tree.Node{"pool_size": {Value: "5"}}}
+	updatedTree := &tree.Node{Children: map[string]*tree.Node{"pool_size": {Value: "10"}}}
+
+	watcher, err := newWatcher[watchedConfig](
+		context.Background(),
+		sequentialFetch(initialTree, updatedTree),
+		WatchOptions{Interval: time.Millisecond},
+	)
+	require.Nil(t, err)
+
+	changed := make(chan struct{})
+	watcher.Subscribe(func(oldConfig, newConfig *watchedConfig) { close(changed) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Run(ctx)
+
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("config was not reloaded")
+	}
+	assert.Equal(t, 10, watcher.Load().PoolSize)
+}
+
+func TestWatcherReloadsChangedOrigins(t *testing.T) {
+	t.Parallel()
+
+	type listConfig struct {
+		Hosts []string `json:"hosts"`
+	}
+	hostsTree := func(paramType string, version string) *tree.Node {
+		return &tree.Node{Children: map[string]*tree.Node{
+			"hosts": {Value: "a,b", Origin: &global.Origin{Name: "/app/hosts", Type: paramType, Version: version}},
+		}}
+	}
+
+	watcher, err := newWatcher[listConfig](
+		context.Background(),
+		sequentialFetch(
+			hostsTree(tree.StringListType, "1"),
+			hostsTree(tree.StringListType, "2"),
+			hostsTree("String", "3"),
+		),
+		WatchOptions{},
+	)
+	require.Nil(t, err)
+	config := watcher.Load()
+
+	require.Nil(t, watcher.Reload(), "new version with the same value")
+	assert.NotSame(t, config, watcher.Load())
+	assert.Equal(t, []string{"a", "b"}, watcher.Load().Hosts)
+	explainedConfig, provenance := watcher.LoadWithProvenance()
+	assert.Same(t, watcher.Load(), explainedConfig)
+	origin, ok := provenance["Hosts[0]"]
+	require.True(t, ok)
+	assert.Equal(t, "2", origin.Version)
+
+	err = watcher.Reload()
+	require.NotNil(t, err, "a String is not split into the slice")
+	assert.ErrorIs(t, err, tree.ErrUnsupportedType)
+
+	watcher.Store(&listConfig{Hosts: []string{"c"}})
+	explainedConfig, provenance = watcher.LoadWithProvenance()
+	assert.Equal(t, []string{"c"}, explainedConfig.Hosts)
+	assert.Nil(t, provenance)
+}
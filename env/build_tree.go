@@ -0,0 +1,46 @@
+package env
+
+import (
+	"strings"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/tree"
+)
+
+// Builds a tree of parameters out of "NAME=value" pairs, as returned by os.Environ.
+func buildEnvTree(environ []string, options LoadConfigOptions) *tree.Node {
+	separator := options.Separator
+	if separator == "" {
+		separator = defaultSeparator
+	}
+
+	paramTree := new(tree.Node)
+
+	for _, variable := range environ {
+		name, value, ok := strings.Cut(variable, "=")
+		if !ok || !strings.HasPrefix(name, options.Prefix) {
+			continue
+		}
+		nameWithoutPrefix := strings.TrimPrefix(name, options.Prefix)
+		if nameWithoutPrefix == "" {
+			continue
+		}
+
+		pathParts := strings.Split(strings.ToLower(nameWithoutPrefix), separator)
+		destination := paramTree
+		for _, part := range pathParts {
+			if destination.Children == nil {
+				destination.Children = make(map[string]*tree.Node)
+			}
+			newDestination, ok := destination.Children[part]
+			if !ok {
+				newDestination = &tree.Node{}
+				destination.Children[part] = newDestination
+			}
+			destination = newDestination
+		}
+		destination.Value = value
+		destination.Origin = &global.Origin{Backend: global.BackendEnvironment, Name: name, Prefix: options.Prefix}
+	}
+	return paramTree
+}
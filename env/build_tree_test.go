@@ -0,0 +1,96 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/tree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func envOrigin(name string) *global.Origin {
+	return &global.Origin{Backend: global.BackendEnvironment, Name: name, Prefix: "APP__"}
+}
+
+func TestBuildEnvTree(t *testing.T) {
+	t.Parallel()
+
+	environ := []string{
+		"APP__DATABASE__POOL_SIZE=10",
+		"APP__DATABASE__URLS__0=postgres://primary",
+		"APP__DATABASE__URLS__1=postgres://replica",
+		"APP__GREETING=a=b",
+		"APP__=ignored",
+		"OTHER__DATABASE__POOL_SIZE=20",
+		"PATH=/usr/bin",
+	}
+
+	expectedTree := &tree.Node{
+		Children: map[string]*tree.Node{
+			"database": {
+				Children: map[string]*tree.Node{
+					"pool_size": {Value: "10", Origin: envOrigin("APP__DATABASE__POOL_SIZE")},
+					"urls": {
+						Children: map[string]*tree.Node{
+							"0": {Value: "postgres://primary", Origin: envOrigin("APP__DATABASE__URLS__0")},
+							"1": {Value: "postgres://replica", Origin: envOrigin("APP__DATABASE__URLS__1")},
+						},
+					},
+				},
+			},
+			"greeting": {Value: "a=b", Origin: envOrigin("APP__GREETING")},
+		},
+	}
+
+	paramTree := buildEnvTree(environ, LoadConfigOptions{Prefix: "APP__"})
+
+	assert.Equal(t, expectedTree, paramTree)
+}
+
+func TestBuildEnvTreeWithCustomSeparator(t *testing.T) {
+	t.Parallel()
+
+	paramTree := buildEnvTree([]string{"APP_DATABASE_HOST=localhost"}, LoadConfigOptions{Prefix: "APP_", Separator: "_"})
+
+	assert.Equal(t, "localhost", paramTree.Children["database"].Children["host"].Value)
+}
+
+func TestLoadConfigFromEnvironment(t *testing.T) { //nolint:paralleltest // modifies the environment
+	type config struct {
+		Database struct {
+			PoolSize int      `json:"pool_size"`
+			URLs     []string `json:"urls"`
+		} `json:"database"`
+		Greeting string
+	}
+
+	t.Setenv("GLOBAL_TEST__DATABASE__POOL_SIZE", "10")
+	t.Setenv("GLOBAL_TEST__DATABASE__URLS__0", "postgres://primary")
+	t.Setenv("GLOBAL_TEST__GREETING", "hello")
+
+	var loadedConfig config
+	err := LoadConfigFromEnvironment(LoadConfigOptions{Prefix: "GLOBAL_TEST__"}, &loadedConfig)
+	require.Nil(t, err)
+
+	assert.Equal(t, 10, loadedConfig.Database.PoolSize)
+	assert.Equal(t, []string{"postgres://primary"}, loadedConfig.Database.URLs)
+	assert.Equal(t, "hello", loadedConfig.Greeting)
+}
+
+func TestLoadConfigFromEnvironmentWithoutPrefix(t *testing.T) { //nolint:paralleltest // modifies the environment
+	t.Setenv("GREETING", "hello")
+
+	var loadedConfig struct {
+		Greeting string
+	}
+	err := LoadConfigFromEnvironment(LoadConfigOptions{IgnoreUnmappedParams: true}, &loadedConfig)
+	require.NotNil(t, err)
+	assert.False(t, err.Warning())
+	assert.Contains(t, err.Error(), "prefix must not be empty")
+	assert.Empty(t, loadedConfig.Greeting, "unrelated variables are not loaded")
+
+	paramTree, err := EnvironmentSource(LoadConfigOptions{})()
+	assert.NotNil(t, err)
+	assert.Nil(t, paramTree)
+}
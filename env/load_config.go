@@ -0,0 +1,71 @@
+package env
+
+import (
+	"os"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/tree"
+	"github.com/railsware/go-global/v2/utils"
+)
+
+const defaultSeparator = "__"
+
+type LoadConfigOptions struct {
+	// Only variables starting with Prefix are loaded, e.g. "APP__". The prefix is stripped from the name.
+	// Prefix is required, so that unrelated variables like PATH or HOME are not loaded.
+	Prefix string
+	// Separator splits variable names into keys. Defaults to "__".
+	Separator string
+	// If IgnoreUnmappedParams is set, a variable with no matching config field will be silently ignored.
+	IgnoreUnmappedParams bool
+	// Provenance, if not nil, collects the origins of the loaded values, see tree.WriteOptions.
+	Provenance global.Provenance
+}
+
+// LoadConfigFromEnvironment reads environment variables and writes them to config.
+//   - config must be a pointer to a struct.
+//   - Variable names are split by the separator, e.g. APP__DATABASE__URLS__0 is written to Database.URLs[0].
+//     Keys are lowercased and matched to struct fields case-insensitively by: name, `global:` tag, or `json:` tag
+func LoadConfigFromEnvironment( //nolint:nonamedreturns // using named return for defer
+	options LoadConfigOptions,
+	globalConfig interface{},
+) (err global.Error) {
+	defer func() {
+		if panicErr := recover(); panicErr != nil {
+			err = global.NewError("global: panic while loading from environment: %v", panicErr)
+			return
+		}
+	}()
+
+	if _, err := utils.ReflectConfig(globalConfig); err != nil {
+		return err
+	}
+	if err := options.validate(); err != nil {
+		return err
+	}
+
+	paramTree := buildEnvTree(os.Environ(), options)
+
+	return paramTree.WriteConfig(globalConfig, tree.WriteOptions{
+		IgnoreUnmappedParams: options.IgnoreUnmappedParams,
+		Provenance:           options.Provenance,
+	})
+}
+
+// EnvironmentSource returns a source of the parameter tree read from environment variables,
+// to be combined with other sources. options.IgnoreUnmappedParams has no effect here.
+func EnvironmentSource(options LoadConfigOptions) tree.Source {
+	return func() (*tree.Node, global.Error) {
+		if err := options.validate(); err != nil {
+			return nil, err
+		}
+		return buildEnvTree(os.Environ(), options), nil
+	}
+}
+
+func (options LoadConfigOptions) validate() global.Error {
+	if options.Prefix == "" {
+		return global.NewError("global: environment variable prefix must not be empty")
+	}
+	return nil
+}
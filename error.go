@@ -1,6 +1,9 @@
 package global
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type Error interface {
 	error
@@ -10,6 +13,7 @@ type Error interface {
 type globalError struct {
 	msg       string
 	isWarning bool
+	cause     error
 }
 
 func (g globalError) Error() string {
@@ -20,10 +24,70 @@ func (g globalError) Warning() bool {
 	return g.isWarning
 }
 
+func (g globalError) Unwrap() error {
+	return g.cause
+}
+
 func NewWarning(msg string, arguments ...interface{}) Error {
-	return &globalError{fmt.Sprintf(msg, arguments...), true}
+	return &globalError{fmt.Sprintf(msg, arguments...), true, nil}
 }
 
 func NewError(msg string, arguments ...interface{}) Error {
-	return &globalError{fmt.Sprintf(msg, arguments...), false}
+	return &globalError{fmt.Sprintf(msg, arguments...), false, nil}
+}
+
+// WrapError returns an error that unwraps to cause, so it can be inspected with errors.Is and errors.As.
+func WrapError(cause error, msg string, arguments ...interface{}) Error {
+	return &globalError{fmt.Sprintf(msg, arguments...), false, cause}
+}
+
+// WrapWarning is WrapError for warnings.
+func WrapWarning(cause error, msg string, arguments ...interface{}) Error {
+	return &globalError{fmt.Sprintf(msg, arguments...), true, cause}
+}
+
+// JoinErrors combines the errors that are not nil. Returns nil if there are none.
+// The result is a warning only if all of the errors are warnings, and it unwraps to each of them.
+func JoinErrors(errs ...Error) Error {
+	joined := joinedError{isWarning: true}
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		joined.errs = append(joined.errs, err)
+		joined.isWarning = joined.isWarning && err.Warning()
+	}
+	switch len(joined.errs) {
+	case 0:
+		return nil
+	case 1:
+		return joined.errs[0]
+	default:
+		return joined
+	}
+}
+
+type joinedError struct {
+	errs      []Error
+	isWarning bool
+}
+
+func (j joinedError) Error() string {
+	msgs := make([]string, 0, len(j.errs))
+	for _, err := range j.errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (j joinedError) Warning() bool {
+	return j.isWarning
+}
+
+func (j joinedError) Unwrap() []error {
+	errs := make([]error, 0, len(j.errs))
+	for _, err := range j.errs {
+		errs = append(errs, err)
+	}
+	return errs
 }
@@ -0,0 +1,37 @@
+package global
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapError(t *testing.T) {
+	t.Parallel()
+
+	err := WrapError(context.Canceled, "global: cancelled: %v", "reason")
+
+	assert.Equal(t, "global: cancelled: reason", err.Error())
+	assert.False(t, err.Warning())
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, errors.Is(NewError("global: failed"), context.Canceled))
+}
+
+func TestJoinErrors(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, JoinErrors(nil, nil))
+
+	warning := WrapWarning(context.DeadlineExceeded, "global: stale")
+	assert.Equal(t, warning, JoinErrors(nil, warning))
+
+	joined := JoinErrors(warning, NewWarning("global: unmapped"))
+	assert.True(t, joined.Warning())
+	assert.Equal(t, "global: stale; global: unmapped", joined.Error())
+	assert.ErrorIs(t, joined, context.DeadlineExceeded)
+
+	joined = JoinErrors(warning, NewError("global: failed"))
+	assert.False(t, joined.Warning())
+}
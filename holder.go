@@ -0,0 +1,87 @@
+package global
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Loader writes the config into the given struct.
+type Loader[T any] func(config *T) Error
+
+// Holder keeps the live config. Load is lock-free, so it can be called from every request handler.
+type Holder[T any] struct {
+	load   Loader[T]
+	config atomic.Pointer[T]
+
+	// mutex guards subscribers and serializes updates
+	mutex       sync.Mutex
+	subscribers []func(oldConfig, newConfig *T)
+}
+
+// NewHolder creates a holder with the config loaded by load. Warnings are returned along with the holder.
+//
+//	holder, err := global.NewHolder(func(config *Config) global.Error {
+//		return globalAWS.LoadConfigFromParameterStore(awsConfig, options, config)
+//	})
+func NewHolder[T any](load Loader[T]) (*Holder[T], Error) {
+	holder := &Holder[T]{load: load}
+	config := new(T)
+	err := load(config)
+	if err != nil && !err.Warning() {
+		return nil, err
+	}
+	holder.config.Store(config)
+	return holder, err
+}
+
+// NewHolderWithConfig creates a holder with an already loaded config. Reload is a no-op for such a holder.
+func NewHolderWithConfig[T any](config *T) *Holder[T] {
+	holder := &Holder[T]{}
+	holder.config.Store(config)
+	return holder
+}
+
+// Load returns the current config. The struct behind the pointer must be treated as read-only.
+func (holder *Holder[T]) Load() *T {
+	return holder.config.Load()
+}
+
+// Subscribe registers a callback invoked after the config has been replaced.
+func (holder *Holder[T]) Subscribe(callback func(oldConfig, newConfig *T)) {
+	holder.mutex.Lock()
+	defer holder.mutex.Unlock()
+	holder.subscribers = append(holder.subscribers, callback)
+}
+
+// Store replaces the config and notifies subscribers.
+func (holder *Holder[T]) Store(newConfig *T) {
+	holder.mutex.Lock()
+	defer holder.mutex.Unlock()
+	holder.store(newConfig)
+}
+
+// Reload runs the loader on a fresh struct and stores the result.
+// Warnings are returned, but don't prevent the replacement. On errors, the current config is kept.
+func (holder *Holder[T]) Reload() Error {
+	if holder.load == nil {
+		return nil
+	}
+
+	holder.mutex.Lock()
+	defer holder.mutex.Unlock()
+
+	newConfig := new(T)
+	err := holder.load(newConfig)
+	if err != nil && !err.Warning() {
+		return err
+	}
+	holder.store(newConfig)
+	return err
+}
+
+func (holder *Holder[T]) store(newConfig *T) {
+	oldConfig := holder.config.Swap(newConfig)
+	for _, callback := range holder.subscribers {
+		callback(oldConfig, newConfig)
+	}
+}
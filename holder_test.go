@@ -0,0 +1,59 @@
+package global
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type holderTestConfig struct {
+	Version int
+}
+
+func TestHolder(t *testing.T) {
+	t.Parallel()
+
+	version := 0
+	var loadErr Error
+	holder, err := NewHolder(func(config *holderTestConfig) Error {
+		version++
+		config.Version = version
+		return loadErr
+	})
+	require.Nil(t, err)
+	initialConfig := holder.Load()
+	assert.Equal(t, &holderTestConfig{Version: 1}, initialConfig)
+
+	var changes [][2]int
+	holder.Subscribe(func(oldConfig, newConfig *holderTestConfig) {
+		changes = append(changes, [2]int{oldConfig.Version, newConfig.Version})
+	})
+
+	require.Nil(t, holder.Reload())
+	assert.Equal(t, &holderTestConfig{Version: 2}, holder.Load())
+	assert.Equal(t, &holderTestConfig{Version: 1}, initialConfig, "old config is left intact")
+
+	loadErr = NewError("failed")
+	assert.Equal(t, loadErr, holder.Reload())
+	assert.Equal(t, 2, holder.Load().Version, "config is kept on errors")
+
+	loadErr = NewWarning("unmapped")
+	assert.Equal(t, loadErr, holder.Reload())
+	assert.Equal(t, 4, holder.Load().Version, "config is replaced on warnings")
+
+	holder.Store(&holderTestConfig{Version: 10})
+	assert.Equal(t, 10, holder.Load().Version)
+
+	assert.Equal(t, [][2]int{{1, 2}, {2, 4}, {4, 10}}, changes)
+}
+
+func TestNewHolderFails(t *testing.T) {
+	t.Parallel()
+
+	holder, err := NewHolder(func(config *holderTestConfig) Error {
+		return NewError("failed")
+	})
+	assert.Nil(t, holder)
+	assert.Equal(t, "failed", err.Error())
+}
@@ -0,0 +1,64 @@
+package layered
+
+import (
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/tree"
+	"github.com/railsware/go-global/v2/utils"
+)
+
+type LoadConfigOptions struct {
+	// If IgnoreUnmappedParams is set, a parameter with no matching config field will be silently ignored.
+	IgnoreUnmappedParams bool
+	// If RequireAllParams is set, every config field must be supplied by a parameter or a default.
+	// Otherwise, only fields tagged `global:",required"` must be.
+	RequireAllParams bool
+	// Provenance, if not nil, collects the origins of the loaded values, see tree.WriteOptions.
+	Provenance global.Provenance
+}
+
+// LoadConfig loads parameter trees from all sources, merges them and writes the result to config.
+//   - config must be a pointer to a struct.
+//   - Sources are merged in order, later sources win: a value replaces a value, nested keys are merged.
+//   - The merged tree is written once, so warnings and errors are reported against the final result.
+func LoadConfig( //nolint:nonamedreturns // using named return for defer
+	options LoadConfigOptions,
+	globalConfig interface{},
+	sources ...tree.Source,
+) (err global.Error) {
+	defer func() {
+		if panicErr := recover(); panicErr != nil {
+			err = global.NewError("global: panic while loading layered config: %v", panicErr)
+			return
+		}
+	}()
+
+	if _, err := utils.ReflectConfig(globalConfig); err != nil {
+		return err
+	}
+
+	paramTree, err := mergeSources(sources)
+	if err != nil && !err.Warning() {
+		return err
+	}
+
+	return global.JoinErrors(err, paramTree.WriteConfig(globalConfig, tree.WriteOptions{
+		RequireAll:           options.RequireAllParams,
+		IgnoreUnmappedParams: options.IgnoreUnmappedParams,
+		Provenance:           options.Provenance,
+	}))
+}
+
+// Merges the trees of all sources. Warnings of the sources are joined and returned along with the tree.
+func mergeSources(sources []tree.Source) (*tree.Node, global.Error) {
+	mergedTree := new(tree.Node)
+	var warnings global.Error
+	for _, source := range sources {
+		sourceTree, err := source()
+		if err != nil && !err.Warning() {
+			return nil, err
+		}
+		warnings = global.JoinErrors(warnings, err)
+		mergedTree = tree.Merge(mergedTree, sourceTree)
+	}
+	return mergedTree, warnings
+}
@@ -0,0 +1,105 @@
+package layered
+
+import (
+	"testing"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/tree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Database struct {
+		Host     string `json:"host"`
+		PoolSize int    `json:"pool_size"`
+	} `json:"database"`
+	Debug bool `json:"debug"`
+}
+
+func staticSource(paramTree *tree.Node) tree.Source {
+	return func() (*tree.Node, global.Error) {
+		return paramTree, nil
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	defaults := staticSource(&tree.Node{
+		Children: map[string]*tree.Node{
+			"database": {
+				Children: map[string]*tree.Node{
+					"host":      {Value: "localhost"},
+					"pool_size": {Value: "5"},
+				},
+			},
+			"debug": {Value: "true"},
+		},
+	})
+	overrides := staticSource(&tree.Node{
+		Children: map[string]*tree.Node{
+			"database": {
+				Children: map[string]*tree.Node{
+					"host": {Value: "db.example.com"},
+				},
+			},
+			"debug": {Value: "false"},
+		},
+	})
+
+	var config testConfig
+	err := LoadConfig(LoadConfigOptions{}, &config, defaults, overrides)
+	require.Nil(t, err)
+
+	assert.Equal(t, "db.example.com", config.Database.Host)
+	assert.Equal(t, 5, config.Database.PoolSize)
+	assert.False(t, config.Debug)
+}
+
+func TestLoadConfigReportsErrorsOfMergedTree(t *testing.T) {
+	t.Parallel()
+
+	broken := staticSource(&tree.Node{
+		Children: map[string]*tree.Node{
+			"database": {
+				Children: map[string]*tree.Node{
+					"pool_size": {Value: "not a number"},
+				},
+			},
+			"unknown": {Value: "foo"},
+		},
+	})
+	fixed := staticSource(&tree.Node{
+		Children: map[string]*tree.Node{
+			"database": {
+				Children: map[string]*tree.Node{
+					"pool_size": {Value: "10"},
+				},
+			},
+		},
+	})
+
+	var config testConfig
+	err := LoadConfig(LoadConfigOptions{}, &config, broken, fixed)
+	require.NotNil(t, err)
+	assert.True(t, err.Warning())
+	assert.Equal(t, "global: unknown: unknown field", err.Error())
+	assert.Equal(t, 10, config.Database.PoolSize)
+
+	err = LoadConfig(LoadConfigOptions{IgnoreUnmappedParams: true}, &config, broken, fixed)
+	assert.Nil(t, err)
+}
+
+func TestLoadConfigStopsOnSourceError(t *testing.T) {
+	t.Parallel()
+
+	failing := func() (*tree.Node, global.Error) {
+		return nil, global.NewError("global: source failed")
+	}
+
+	var config testConfig
+	err := LoadConfig(LoadConfigOptions{}, &config, failing)
+	require.NotNil(t, err)
+	assert.Equal(t, "global: source failed", err.Error())
+}
@@ -0,0 +1,47 @@
+package global
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Backends of config values, as set in Origin.Backend.
+const (
+	BackendParameterStore = "Parameter Store"
+	BackendSecretsManager = "Secrets Manager"
+	BackendYAML           = "YAML"
+	BackendEnvironment    = "environment"
+	BackendDefault        = "default"
+)
+
+// Origin describes where a config value came from.
+type Origin struct {
+	// Backend the value was loaded from, one of the Backend* constants.
+	Backend string
+	// Name of the param, e.g. "/shared/database/host".
+	Name string
+	// Prefix the param was loaded with, e.g. "/shared/".
+	Prefix string
+	// Type of the param in its backend, e.g. "StringList" for Parameter Store.
+	Type string
+	// Version of the param, if the backend keeps versions.
+	Version string
+	// LastModified is the time the param was last changed, if the backend reports it.
+	LastModified time.Time
+}
+
+// String describes the origin for logs, e.g. "/app/database/pool_size (Parameter Store, version 3)".
+func (origin Origin) String() string {
+	details := []string{origin.Backend}
+	if origin.Version != "" {
+		details = append(details, "version "+origin.Version)
+	}
+	if !origin.LastModified.IsZero() {
+		details = append(details, "modified "+origin.LastModified.Format(time.RFC3339))
+	}
+	if origin.Name == "" {
+		return strings.Join(details, ", ")
+	}
+	return fmt.Sprintf("%s (%s)", origin.Name, strings.Join(details, ", "))
+}
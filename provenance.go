@@ -0,0 +1,26 @@
+package global
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Provenance maps Go field paths of a config, e.g. "Database.PoolSize", "URLs[0]" or `Limits["api"]`,
+// to the origins of their values. Loaders fill the provenance passed in their options.
+type Provenance map[string]Origin
+
+// String lists the origins of all fields, sorted by field path, one per line.
+func (provenance Provenance) String() string {
+	fieldPaths := make([]string, 0, len(provenance))
+	for fieldPath := range provenance {
+		fieldPaths = append(fieldPaths, fieldPath)
+	}
+	sort.Strings(fieldPaths)
+
+	var builder strings.Builder
+	for _, fieldPath := range fieldPaths {
+		fmt.Fprintf(&builder, "%s: %s\n", fieldPath, provenance[fieldPath])
+	}
+	return builder.String()
+}
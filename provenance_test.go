@@ -0,0 +1,32 @@
+package global
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvenanceString(t *testing.T) {
+	t.Parallel()
+
+	provenance := Provenance{
+		"PoolSize": {
+			Backend:      BackendParameterStore,
+			Name:         "/app/pool_size",
+			Version:      "3",
+			LastModified: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		"Host": {Backend: BackendDefault},
+	}
+	assert.Equal(
+		t,
+		"/app/pool_size (Parameter Store, version 3, modified 2024-01-02T03:04:05Z)",
+		provenance["PoolSize"].String(),
+	)
+	assert.Equal(
+		t,
+		"Host: default\nPoolSize: /app/pool_size (Parameter Store, version 3, modified 2024-01-02T03:04:05Z)\n",
+		provenance.String(),
+	)
+}
@@ -0,0 +1,291 @@
+// Package redact renders loaded configs with sensitive values masked, so that they can be logged.
+package redact
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/tree"
+	"github.com/railsware/go-global/v2/utils"
+	goyaml "gopkg.in/yaml.v3"
+)
+
+type Format int
+
+const (
+	// JSON renders the config as an indented JSON object.
+	JSON Format = iota
+	// YAML renders the config as a YAML document.
+	YAML
+	// Text renders one "name = value" line per value, with param names like "database/pool_size".
+	Text
+)
+
+// Mask replaces sensitive values.
+const Mask = "******"
+
+const (
+	keyValueSeparator  = " = "
+	textPathSeparator  = "/"
+	jsonIndent         = "  "
+	yamlIndentInSpaces = 2
+)
+
+var (
+	durationType      = reflect.TypeOf(time.Duration(0))
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// Dump renders the config in the format, replacing with Mask the values that are
+//   - in fields tagged `global:",sensitive"`, including everything nested in them;
+//   - loaded from SecureString params or from Secrets Manager, according to provenance,
+//     as collected by the loader, see tree.WriteOptions.Provenance. provenance may be nil.
+//
+// Fields are named like params: by the `global:` tag, the `json:` tag or the field name.
+// Zero values are not masked, so that missing secrets can be spotted.
+func Dump(config interface{}, provenance global.Provenance, format Format) ([]byte, global.Error) {
+	reflectedConfig, err := utils.ReflectConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	walker := walker{provenance: provenance}
+	dumped := walker.walk(reflectedConfig, "", false)
+
+	var (
+		output    []byte
+		formatErr error
+	)
+	switch format {
+	case JSON:
+		output, formatErr = json.MarshalIndent(dumped, "", jsonIndent)
+	case YAML:
+		var buffer bytes.Buffer
+		encoder := goyaml.NewEncoder(&buffer)
+		encoder.SetIndent(yamlIndentInSpaces)
+		formatErr = encoder.Encode(dumped)
+		output = buffer.Bytes()
+	case Text:
+		var buffer bytes.Buffer
+		writeText(&buffer, "", dumped)
+		output = buffer.Bytes()
+	default:
+		return nil, global.NewError("global: unsupported dump format %d", format)
+	}
+	if formatErr != nil {
+		return nil, global.WrapError(formatErr, "global: cannot dump config: %v", formatErr)
+	}
+	return output, nil
+}
+
+// walker converts a config into nested objects, slices and plain values.
+type walker struct {
+	provenance global.Provenance
+}
+
+// Converts the value at the Go field path. sensitive is set within fields tagged as sensitive.
+func (walker walker) walk(value reflect.Value, fieldPath string, sensitive bool) interface{} {
+	if !value.IsValid() {
+		return nil
+	}
+	if (sensitive || walker.isSecret(fieldPath)) && !value.IsZero() {
+		return Mask
+	}
+
+	switch {
+	case value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return walker.walk(value.Elem(), fieldPath, sensitive)
+	case value.Type() == durationType:
+		return value.Interface().(time.Duration).String() //nolint:forcetypeassert // checked above
+	case isLeaf(value.Type()):
+		if text, ok := formatLeaf(value); ok {
+			return text
+		}
+	}
+
+	switch value.Kind() { //nolint:exhaustive // other kinds are rendered with fmt
+	case reflect.Struct:
+		return walker.walkStruct(value, fieldPath, sensitive)
+	case reflect.Map:
+		return walker.walkMap(value, fieldPath, sensitive)
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil
+		}
+		elements := make([]interface{}, 0, value.Len())
+		for index := 0; index < value.Len(); index++ {
+			elementPath := fmt.Sprintf("%s[%d]", fieldPath, index)
+			elements = append(elements, walker.walk(value.Index(index), elementPath, sensitive))
+		}
+		return elements
+	case reflect.Bool:
+		return value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return value.Uint()
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	case reflect.String:
+		return value.String()
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+func (walker walker) walkStruct(value reflect.Value, fieldPath string, sensitive bool) interface{} {
+	var dumped object
+	for _, structField := range reflect.VisibleFields(value.Type()) {
+		if !structField.IsExported() || structField.Anonymous || utils.TagName(structField, "json") == "-" {
+			continue
+		}
+		field, err := value.FieldByIndexErr(structField.Index)
+		if err != nil {
+			// promoted through a nil embedded pointer
+			continue
+		}
+		fieldTag, _ := utils.ParseFieldTag(structField)
+		childPath := structField.Name
+		if fieldPath != "" {
+			childPath = fieldPath + "." + structField.Name
+		}
+		dumped = append(dumped, entry{
+			key:   utils.ParamName(structField),
+			value: walker.walk(field, childPath, sensitive || fieldTag.Sensitive),
+		})
+	}
+	return dumped
+}
+
+func (walker walker) walkMap(value reflect.Value, fieldPath string, sensitive bool) interface{} {
+	if value.IsNil() {
+		return nil
+	}
+	keys := value.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	dumped := make(object, 0, len(keys))
+	for _, key := range keys {
+		keyString := fmt.Sprint(key.Interface())
+		elementPath := fmt.Sprintf("%s[%q]", fieldPath, keyString)
+		dumped = append(dumped, entry{key: keyString, value: walker.walk(value.MapIndex(key), elementPath, sensitive)})
+	}
+	return dumped
+}
+
+// Reports whether values of the type have no fields or elements to walk, so that they can be formatted as a whole.
+// Other structs may have sensitive fields, which their String or MarshalText would reveal.
+func isLeaf(valueType reflect.Type) bool {
+	switch valueType.Kind() { //nolint:exhaustive // other kinds have no children
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return tree.IsDecodable(valueType)
+	default:
+		return true
+	}
+}
+
+// Formats the value with MarshalText or String, if its type or a pointer to it implements them.
+func formatLeaf(value reflect.Value) (string, bool) {
+	pointer := reflect.New(value.Type())
+	pointer.Elem().Set(value)
+	switch {
+	case pointer.Type().Implements(textMarshalerType):
+		text, err := pointer.Interface().(encoding.TextMarshaler).MarshalText() //nolint:forcetypeassert // checked above
+		if err != nil {
+			return fmt.Sprint(value.Interface()), true
+		}
+		return string(text), true
+	case pointer.Type().Implements(stringerType):
+		return pointer.Interface().(fmt.Stringer).String(), true //nolint:forcetypeassert // checked above
+	default:
+		return "", false
+	}
+}
+
+// Reports whether the value at the field path was loaded from an encrypted param or a secret.
+func (walker walker) isSecret(fieldPath string) bool {
+	origin, ok := walker.provenance[fieldPath]
+	return ok && (origin.Type == tree.SecureStringType || origin.Backend == global.BackendSecretsManager)
+}
+
+// object is a struct or a map, keeping the order of its entries.
+type object []entry
+
+type entry struct {
+	key   string
+	value interface{}
+}
+
+func (dumped object) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for index, entry := range dumped {
+		if index > 0 {
+			buffer.WriteByte(',')
+		}
+		key, err := json.Marshal(entry.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(entry.value)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+func (dumped object) MarshalYAML() (interface{}, error) {
+	mapping := &goyaml.Node{Kind: goyaml.MappingNode}
+	for _, entry := range dumped {
+		keyNode := &goyaml.Node{Kind: goyaml.ScalarNode, Value: entry.key}
+		valueNode := &goyaml.Node{}
+		if err := valueNode.Encode(entry.value); err != nil {
+			return nil, err
+		}
+		mapping.Content = append(mapping.Content, keyNode, valueNode)
+	}
+	return mapping, nil
+}
+
+// Writes a "name = value" line for every plain value.
+func writeText(buffer *bytes.Buffer, path string, dumped interface{}) {
+	switch dumped := dumped.(type) {
+	case object:
+		for _, entry := range dumped {
+			writeText(buffer, joinTextPath(path, entry.key), entry.value)
+		}
+	case []interface{}:
+		for index, element := range dumped {
+			writeText(buffer, joinTextPath(path, strconv.Itoa(index)), element)
+		}
+	case nil:
+		buffer.WriteString(path + keyValueSeparator + "null\n")
+	default:
+		buffer.WriteString(path + keyValueSeparator + fmt.Sprint(dumped) + "\n")
+	}
+}
+
+func joinTextPath(parent string, child string) string {
+	if parent == "" {
+		return child
+	}
+	return strings.Join([]string{parent, child}, textPathSeparator)
+}
@@ -0,0 +1,169 @@
+package redact
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type redactTestConfig struct {
+	Database struct {
+		URL      url.URL       `json:"url"`
+		Password string        `json:"password" global:",sensitive"`
+		Token    string        `json:"token"`
+		Timeout  time.Duration `json:"timeout"`
+		Replicas []string      `json:"replicas"`
+	} `json:"database"`
+	Keys    map[string]string `json:"keys" global:",sensitive"`
+	Limits  map[string]int    `json:"limits"`
+	Missing string            `json:"missing" global:",sensitive"`
+	Next    *redactTestConfig `json:"next"`
+}
+
+var redactTestProvenance = global.Provenance{
+	"Database.Token":       {Backend: global.BackendParameterStore, Type: "SecureString"},
+	"Database.Replicas[1]": {Backend: global.BackendSecretsManager},
+	"Database.Timeout":     {Backend: global.BackendParameterStore, Type: "String"},
+}
+
+func newRedactTestConfig() *redactTestConfig {
+	config := &redactTestConfig{}
+	config.Database.URL = url.URL{Scheme: "postgres", Host: "db.internal"}
+	config.Database.Password = "hunter2"
+	config.Database.Token = "from-ssm"
+	config.Database.Timeout = 30 * time.Second
+	config.Database.Replicas = []string{"r1", "r2"}
+	config.Keys = map[string]string{"api": "secret"}
+	config.Limits = map[string]int{"b": 2, "a": 1}
+	return config
+}
+
+func TestDumpJSON(t *testing.T) {
+	t.Parallel()
+
+	output, err := Dump(newRedactTestConfig(), redactTestProvenance, JSON)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{
+		"database": {
+			"url": "postgres://db.internal",
+			"password": "******",
+			"token": "******",
+			"timeout": "30s",
+			"replicas": ["r1", "******"]
+		},
+		"keys": "******",
+		"limits": {"a": 1, "b": 2},
+		"missing": "",
+		"next": null
+	}`, string(output))
+}
+
+func TestDumpYAML(t *testing.T) {
+	t.Parallel()
+
+	output, err := Dump(newRedactTestConfig(), redactTestProvenance, YAML)
+	require.Nil(t, err)
+	assert.Equal(t, `database:
+  url: postgres://db.internal
+  password: '******'
+  token: '******'
+  timeout: 30s
+  replicas:
+    - r1
+    - '******'
+keys: '******'
+limits:
+  a: 1
+  b: 2
+missing: ""
+next: null
+`, string(output))
+}
+
+func TestDumpText(t *testing.T) {
+	t.Parallel()
+
+	output, err := Dump(newRedactTestConfig(), redactTestProvenance, Text)
+	require.Nil(t, err)
+	assert.Equal(t, `database/url = postgres://db.internal
+database/password = ******
+database/token = ******
+database/timeout = 30s
+database/replicas/0 = r1
+database/replicas/1 = ******
+keys = ******
+limits/a = 1
+limits/b = 2
+missing = 
+next = null
+`, string(output))
+}
+
+func TestDumpErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := Dump(redactTestConfig{}, nil, JSON)
+	assert.NotNil(t, err)
+
+	_, err = Dump(&redactTestConfig{}, nil, Format(42))
+	assert.NotNil(t, err)
+}
+
+func TestDumpCopy(t *testing.T) {
+	t.Parallel()
+
+	config := newRedactTestConfig()
+	copied := *config
+	output, err := Dump(&copied, redactTestProvenance, Text)
+	require.Nil(t, err)
+	assert.Contains(t, string(output), "database/token = ******\n", "provenance is not tied to the config pointer")
+}
+
+type redactTestCredentials struct {
+	User     string `json:"user"`
+	Password string `json:"password" global:",sensitive"`
+}
+
+func (credentials *redactTestCredentials) String() string {
+	return credentials.User + ":" + credentials.Password
+}
+
+func TestDumpStringerWithSensitiveFields(t *testing.T) {
+	t.Parallel()
+
+	var config struct {
+		Credentials redactTestCredentials `json:"credentials"`
+	}
+	config.Credentials = redactTestCredentials{User: "admin", Password: "hunter2"}
+
+	output, err := Dump(&config, nil, Text)
+	require.Nil(t, err)
+	assert.Equal(t, "credentials/user = admin\ncredentials/password = ******\n", string(output))
+}
+
+func TestDumpSensitiveParent(t *testing.T) {
+	t.Parallel()
+
+	var config struct {
+		Database struct {
+			Host    string `json:"host"`
+			Options struct {
+				Token string `json:"token"`
+			} `json:"options"`
+		} `json:"database" global:",sensitive"`
+	}
+	config.Database.Options.Token = "secret"
+
+	// fields within a sensitive struct are masked, unless they are zero
+	dumped := walker{}.walkStruct(reflect.ValueOf(config.Database), "Database", true)
+	assert.Equal(t, object{{key: "host", value: ""}, {key: "options", value: Mask}}, dumped)
+
+	output, err := Dump(&config, nil, Text)
+	require.Nil(t, err)
+	assert.Equal(t, "database = ******\n", string(output))
+}
@@ -0,0 +1,127 @@
+package tree
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteArrays(t *testing.T) {
+	t.Parallel()
+
+	var destination struct {
+		Replicas [3]string `json:"replicas"`
+		Ports    [2]int    `json:"ports" split:","`
+		Servers  [2]struct {
+			Host string `json:"host"`
+		} `json:"servers"`
+		Pointer *[2]bool `json:"pointer"`
+	}
+	destination.Replicas[2] = "kept"
+	tree := &Node{
+		Children: map[string]*Node{
+			"replicas": {Children: map[string]*Node{"0": {Value: "r0"}, "1": {Value: "r1"}}},
+			"ports":    {Value: "80,443"},
+			"servers":  {Children: map[string]*Node{"1": {Children: map[string]*Node{"host": {Value: "s1"}}}}},
+			"pointer":  {Children: map[string]*Node{"1": {Value: "true"}}},
+		},
+	}
+
+	errs := tree.Write(reflect.ValueOf(&destination))
+	require.False(t, errs.Present(), errs.Error())
+
+	assert.Equal(t, [3]string{"r0", "r1", "kept"}, destination.Replicas)
+	assert.Equal(t, [2]int{80, 443}, destination.Ports)
+	assert.Equal(t, "", destination.Servers[0].Host)
+	assert.Equal(t, "s1", destination.Servers[1].Host)
+	require.NotNil(t, destination.Pointer)
+	assert.Equal(t, [2]bool{false, true}, *destination.Pointer)
+}
+
+func TestWriteArraysErrors(t *testing.T) {
+	t.Parallel()
+
+	var destination struct {
+		Replicas [2]string `json:"replicas"`
+		Ports    [1]int    `json:"ports" split:","`
+	}
+	tree := &Node{
+		Children: map[string]*Node{
+			"replicas": {Children: map[string]*Node{"0": {Value: "r0"}, "2": {Value: "r2"}, "x": {Value: "rx"}}},
+			"ports":    {Value: "80,443"},
+		},
+	}
+
+	errs := tree.Write(reflect.ValueOf(&destination))
+	require.True(t, errs.Present())
+	assert.False(t, errs.Warning())
+	assert.Equal(t, "r0", destination.Replicas[0])
+	assert.Equal(t, 80, destination.Ports[0])
+
+	byPath := make(map[string]*WriteError)
+	for _, err := range errs.Errors() {
+		byPath[err.Path] = err
+	}
+	require.Len(t, byPath, 3)
+	assert.Equal(t, "replicas/2: index out of range of array of length 2", byPath["replicas/2"].Error())
+	assert.Equal(t, "Replicas[2]", byPath["replicas/2"].FieldPath)
+	assert.ErrorIs(t, byPath["replicas/2"], ErrOutOfRange)
+	assert.False(t, byPath["replicas/2"].Warning())
+	assert.Equal(t, "replicas/x: not a numeric index", byPath["replicas/x"].Error())
+	assert.ErrorIs(t, byPath["replicas/x"], ErrBadIndex)
+	assert.Equal(t, "Ports[1]", byPath["ports/1"].FieldPath)
+	assert.ErrorIs(t, byPath["ports/1"], ErrOutOfRange)
+}
+
+func TestWriteConfigArrayOverflowIsNotIgnored(t *testing.T) {
+	t.Parallel()
+
+	var destination struct {
+		Ports [1]int `json:"ports" split:","`
+	}
+	tree := &Node{Children: map[string]*Node{"ports": {Value: "80,443"}}}
+	err := tree.WriteConfig(&destination, WriteOptions{IgnoreUnmappedParams: true})
+	require.NotNil(t, err)
+	assert.False(t, err.Warning())
+	assert.ErrorIs(t, err, ErrOutOfRange)
+
+	var rootConfig [3]string
+	tree = &Node{Children: map[string]*Node{"0": {Value: "a"}, "5": {Value: "f"}}}
+	err = tree.WriteConfig(&rootConfig, WriteOptions{IgnoreUnmappedParams: true})
+	require.NotNil(t, err)
+	assert.False(t, err.Warning())
+	assert.Equal(t, "global: 5: index out of range of array of length 3", err.Error())
+}
+
+func TestWriteConfigIntoRootArray(t *testing.T) {
+	t.Parallel()
+
+	tree := &Node{
+		Children: map[string]*Node{
+			"0": {Children: map[string]*Node{"host": {Value: "a.internal"}}},
+			"1": {Children: map[string]*Node{"host": {Value: "b.internal"}}},
+		},
+	}
+
+	var config [2]struct {
+		Host string `json:"host"`
+	}
+	require.Nil(t, tree.WriteConfig(&config, WriteOptions{}))
+	assert.Equal(t, "a.internal", config[0].Host)
+	assert.Equal(t, "b.internal", config[1].Host)
+
+	var shortConfig [1]struct {
+		Host string `json:"host"`
+	}
+	err := tree.WriteConfig(&shortConfig, WriteOptions{})
+	require.NotNil(t, err)
+	assert.False(t, err.Warning())
+	assert.Equal(t, "global: 1: index out of range of array of length 1", err.Error())
+	assert.Equal(t, "a.internal", shortConfig[0].Host)
+
+	readTree, err := ReadConfig(&config)
+	require.Nil(t, err)
+	assert.True(t, tree.Equal(readTree))
+}
@@ -0,0 +1,142 @@
+package tree
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/utils"
+)
+
+// ConfigParam is a param that a config field is written from.
+type ConfigParam struct {
+	// Path of the param relative to the root of the tree, separated with slashes.
+	Path string
+	// FieldPath is the path of the Go value, e.g. Database.PoolSize.
+	FieldPath string
+	// FieldTag holds the options of the closest struct field.
+	FieldTag utils.FieldTag
+}
+
+// ConfigParams lists the params that the fields of config are written from, in field order.
+//   - config must be a pointer to a struct.
+//   - Params are named like ReadConfig names children: by the `global:` tag, the `json:` tag or the field name.
+//   - A slice must be tagged with `split:`, to be written from a single list param.
+//     An array has a param per element, unless it is tagged with `split:` too.
+//   - Maps and interfaces cannot be listed, as the names of their params are not known in advance.
+func ConfigParams(config interface{}) ([]ConfigParam, global.Error) {
+	reflectedConfig, err := utils.ReflectConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var params []ConfigParam
+	var errors WriteErrors
+	listConfigParams(reflectedConfig.Type(), "", "", utils.FieldTag{}, map[reflect.Type]bool{}, &params, &errors)
+	if errors.Present() {
+		return nil, errors.Join()
+	}
+	return params, nil
+}
+
+// Appends the params of a value of the type at the path to params.
+// visiting holds the struct types being listed up the stack, so that recursive types are not listed forever.
+func listConfigParams(
+	valueType reflect.Type,
+	path string,
+	fieldPath string,
+	fieldTag utils.FieldTag,
+	visiting map[reflect.Type]bool,
+	params *[]ConfigParam,
+	errors *WriteErrors,
+) {
+	if valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+
+	if isDecodable(valueType) {
+		*params = append(*params, ConfigParam{Path: path, FieldPath: fieldPath, FieldTag: fieldTag})
+		return
+	}
+
+	switch valueType.Kind() { //nolint:exhaustive // other kinds are leaves or unsupported
+	case reflect.Struct:
+		if visiting[valueType] {
+			return
+		}
+		visiting[valueType] = true
+		defer delete(visiting, valueType)
+
+		for _, structField := range reflect.VisibleFields(valueType) {
+			if !structField.IsExported() || structField.Anonymous || utils.TagName(structField, "json") == "-" {
+				continue
+			}
+			childPath := joinParamPath(path, utils.ParamName(structField))
+			childFieldPath := joinFieldPath(fieldPath, structField.Name)
+			childFieldTag, err := utils.ParseFieldTag(structField)
+			if err != nil {
+				errors.append(WriteError{
+					Path: childPath, FieldPath: childFieldPath, Kind: ErrInvalidTag, Err: err, msg: err.Error(),
+				})
+				continue
+			}
+			listConfigParams(structField.Type, childPath, childFieldPath, childFieldTag, visiting, params, errors)
+		}
+	case reflect.Array:
+		if fieldTag.Split != "" {
+			*params = append(*params, ConfigParam{Path: path, FieldPath: fieldPath, FieldTag: fieldTag})
+			return
+		}
+		for index := 0; index < valueType.Len(); index++ {
+			childPath := joinParamPath(path, strconv.Itoa(index))
+			childFieldPath := joinFieldPath(fieldPath, fmt.Sprintf("[%d]", index))
+			listConfigParams(valueType.Elem(), childPath, childFieldPath, fieldTag, visiting, params, errors)
+		}
+	case reflect.Slice:
+		elementType := valueType.Elem()
+		if elementType.Kind() == reflect.Ptr {
+			elementType = elementType.Elem()
+		}
+		switch elementType.Kind() { //nolint:exhaustive // only elements with children can't be split from a list
+		case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array, reflect.Interface:
+			if !isDecodable(elementType) {
+				errors.append(WriteError{
+					Path:      path,
+					FieldPath: fieldPath,
+					Kind:      ErrUnsupportedType,
+					msg:       fmt.Sprintf("cannot list params of slice of %v", elementType.Kind()),
+				})
+				return
+			}
+		}
+		// without a split tag, the elements could be stored as params named by their index,
+		// which cannot be listed in advance
+		if fieldTag.Split == "" {
+			errors.append(WriteError{
+				Path:      path,
+				FieldPath: fieldPath,
+				Kind:      ErrUnsupportedType,
+				msg:       "cannot list params of slice without split tag",
+			})
+			return
+		}
+		*params = append(*params, ConfigParam{Path: path, FieldPath: fieldPath, FieldTag: fieldTag})
+	case reflect.Map, reflect.Interface, reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128:
+		errors.append(WriteError{
+			Path:      path,
+			FieldPath: fieldPath,
+			Kind:      ErrUnsupportedType,
+			msg:       fmt.Sprintf("cannot list params of %v", valueType.Kind()),
+		})
+	default:
+		*params = append(*params, ConfigParam{Path: path, FieldPath: fieldPath, FieldTag: fieldTag})
+	}
+}
+
+func joinParamPath(parent string, child string) string {
+	if parent == "" {
+		return child
+	}
+	return fmt.Sprintf("%s/%s", parent, child)
+}
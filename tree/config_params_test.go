@@ -0,0 +1,101 @@
+package tree
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func configParamPaths(params []ConfigParam) []string {
+	paths := make([]string, 0, len(params))
+	for _, param := range params {
+		paths = append(paths, param.Path)
+	}
+	return paths
+}
+
+func TestConfigParams(t *testing.T) {
+	t.Parallel()
+
+	type recursive struct {
+		Name string     `json:"name"`
+		Next *recursive `json:"next"`
+	}
+	type embedded struct {
+		Region string `json:"region"`
+	}
+	var config struct {
+		embedded
+		Database struct {
+			Host     string        `json:"host"`
+			Password string        `json:"password" global:",sensitive"`
+			Timeout  time.Duration `json:"timeout"`
+			Replicas []string      `json:"replicas" split:","`
+		} `json:"database"`
+		URL      *url.URL    `json:"url"`
+		Hosts    [2]string   `json:"hosts"`
+		Greeting string      `global:"hello"`
+		Tree     recursive   `json:"tree"`
+		Skipped  string      `json:"-"`
+		private  string      //nolint:unused // must be skipped
+		Ports    []int       `json:"ports" split:","`
+		Nested   [1]embedded `json:"nested"`
+		Counter  *int        `json:"counter"`
+	}
+
+	params, err := ConfigParams(&config)
+	require.Nil(t, err)
+
+	assert.Equal(t, []string{
+		"region",
+		"database/host",
+		"database/password",
+		"database/timeout",
+		"database/replicas",
+		"url",
+		"hosts/0",
+		"hosts/1",
+		"hello",
+		"tree/name",
+		"ports",
+		"nested/0/region",
+		"counter",
+	}, configParamPaths(params))
+
+	assert.Equal(t, "Database.Password", params[2].FieldPath)
+	assert.True(t, params[2].FieldTag.Sensitive)
+	assert.Equal(t, ",", params[4].FieldTag.Split)
+	assert.Equal(t, "Hosts[1]", params[7].FieldPath)
+	assert.Equal(t, "Nested[0].Region", params[11].FieldPath)
+}
+
+func TestConfigParamsErrors(t *testing.T) {
+	t.Parallel()
+
+	var config struct {
+		Limits  map[string]int `json:"limits"`
+		Servers []struct {
+			Host string `json:"host"`
+		} `json:"servers"`
+		Bad   string   `json:"bad" unit:"parsec"`
+		Ports []string `json:"ports"`
+	}
+
+	_, err := ConfigParams(&config)
+	require.NotNil(t, err)
+	assert.False(t, err.Warning())
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	assert.Equal(
+		t,
+		"global: limits: cannot list params of map, servers: cannot list params of slice of struct, "+
+			`bad: invalid unit "parsec", ports: cannot list params of slice without split tag`,
+		err.Error(),
+	)
+
+	_, err = ConfigParams(config)
+	assert.NotNil(t, err)
+}
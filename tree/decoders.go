@@ -0,0 +1,55 @@
+package tree
+
+import (
+	"net/url"
+	"reflect"
+	"sync"
+)
+
+// DecodeFunc parses a raw parameter value into destination, which is a settable value of the registered type.
+type DecodeFunc func(value string, destination reflect.Value) error
+
+var (
+	decodersMutex sync.RWMutex
+	decoders      = map[reflect.Type]DecodeFunc{
+		reflect.TypeOf(url.URL{}): decodeURL,
+	}
+)
+
+// RegisterDecoder registers the function used to write parameter values into values of valueType.
+// Registered decoders take precedence over encoding.TextUnmarshaler and built-in conversions.
+func RegisterDecoder(valueType reflect.Type, decode DecodeFunc) {
+	decodersMutex.Lock()
+	defer decodersMutex.Unlock()
+	decoders[valueType] = decode
+}
+
+// RegisterDecoderFunc is RegisterDecoder for a function returning the decoded value, e.g.
+//
+//	tree.RegisterDecoderFunc(func(value string) (Level, error) { ... })
+func RegisterDecoderFunc[T any](decode func(value string) (T, error)) {
+	RegisterDecoder(reflect.TypeOf((*T)(nil)).Elem(), func(value string, destination reflect.Value) error {
+		decoded, err := decode(value)
+		if err != nil {
+			return err
+		}
+		destination.Set(reflect.ValueOf(&decoded).Elem())
+		return nil
+	})
+}
+
+func lookupDecoder(valueType reflect.Type) (DecodeFunc, bool) {
+	decodersMutex.RLock()
+	defer decodersMutex.RUnlock()
+	decode, ok := decoders[valueType]
+	return decode, ok
+}
+
+func decodeURL(value string, destination reflect.Value) error {
+	parsedURL, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	destination.Set(reflect.ValueOf(*parsedURL))
+	return nil
+}
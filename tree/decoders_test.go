@@ -0,0 +1,118 @@
+package tree
+
+import (
+	"errors"
+	"math/big"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLevel int
+
+func (level *testLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*level = 1
+	case "high":
+		*level = 2
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+type testJSONValue struct {
+	Raw string
+}
+
+func (value *testJSONValue) UnmarshalJSON(data []byte) error {
+	value.Raw = string(data)
+	return nil
+}
+
+type testRegisteredType struct {
+	Parts []string
+}
+
+type testDecodersStruct struct {
+	IP         net.IP               `json:"ip"`
+	URL        *url.URL             `json:"url"`
+	BigInt     *big.Int             `json:"bigint"`
+	Level      testLevel            `json:"level"`
+	Levels     map[string]testLevel `json:"levels"`
+	JSON       testJSONValue        `json:"json"`
+	JSONString testJSONValue        `json:"json_string"`
+	Registered testRegisteredType   `json:"registered"`
+	IntPointer *int                 `json:"int_pointer"`
+}
+
+func TestWriteDecodedValues(t *testing.T) {
+	t.Parallel()
+
+	RegisterDecoderFunc(func(value string) (testRegisteredType, error) {
+		return testRegisteredType{Parts: strings.Split(value, "|")}, nil
+	})
+
+	tree := &Node{
+		Children: map[string]*Node{
+			"ip":          {Value: "10.0.0.1"},
+			"url":         {Value: "https://example.com/path"},
+			"bigint":      {Value: "123456789012345678901234567890"},
+			"level":       {Value: "high"},
+			"levels":      {Children: map[string]*Node{"foo": {Value: "low"}}},
+			"json":        {Value: `{"foo":1}`},
+			"json_string": {Value: "plain string"},
+			"registered":  {Value: "a|b"},
+			"int_pointer": {Value: "42"},
+		},
+	}
+
+	var destination testDecodersStruct
+	errors := tree.Write(reflect.ValueOf(&destination))
+	require.False(t, errors.Present(), errors.Error())
+
+	expectedBigInt, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	intValue := 42
+
+	assert.Equal(t, net.ParseIP("10.0.0.1"), destination.IP)
+	assert.Equal(t, &url.URL{Scheme: "https", Host: "example.com", Path: "/path"}, destination.URL)
+	assert.Equal(t, expectedBigInt, destination.BigInt)
+	assert.Equal(t, testLevel(2), destination.Level)
+	assert.Equal(t, map[string]testLevel{"foo": 1}, destination.Levels)
+	assert.Equal(t, testJSONValue{Raw: `{"foo":1}`}, destination.JSON)
+	assert.Equal(t, testJSONValue{Raw: `"plain string"`}, destination.JSONString)
+	assert.Equal(t, testRegisteredType{Parts: []string{"a", "b"}}, destination.Registered)
+	assert.Equal(t, &intValue, destination.IntPointer)
+}
+
+func TestWriteDecodedValuesErrors(t *testing.T) {
+	t.Parallel()
+
+	tree := &Node{
+		Children: map[string]*Node{
+			"ip":          {Value: "not an ip"},
+			"level":       {Value: "medium"},
+			"int_pointer": {Value: "not an int"},
+		},
+	}
+
+	var destination testDecodersStruct
+	errors := tree.Write(reflect.ValueOf(&destination))
+
+	expected := []expectedError{
+		{"ip", "IP", "not an ip", ErrParse, "cannot read net.IP param value: invalid IP address: not an ip"},
+		{"level", "Level", "medium", ErrParse, "cannot read tree.testLevel param value: unknown level"},
+		{
+			"int_pointer", "IntPointer", "not an int", ErrParse,
+			`cannot read int param value: strconv.ParseInt: parsing "not an int": invalid syntax`,
+		},
+	}
+	assert.ElementsMatch(t, expected, expectedErrors(errors))
+	assert.Nil(t, destination.IntPointer, "pointer is not set on errors")
+}
@@ -0,0 +1,108 @@
+package tree
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testReplica struct {
+	Host string `json:"host"`
+	Port int    `json:"port" default:"5432"`
+}
+
+type testNoDefaults struct {
+	Host string `json:"host"`
+}
+
+type testRecursive struct {
+	Name string         `json:"name" default:"node"`
+	Next *testRecursive `json:"next"`
+}
+
+type testDefaultsStruct struct {
+	PoolSize   int                    `json:"pool_size,omitempty" default:"10"`
+	Host       string                 `json:"host" default:"localhost"`
+	Debug      bool                   `json:"debug" default:"true"`
+	Timeout    time.Duration          `json:"timeout" default:"30s"`
+	Retries    *int                   `json:"retries" default:"3"`
+	Tags       []string               `json:"tags"`
+	Primary    testReplica            `json:"primary"`
+	Fallback   *testReplica           `json:"fallback"`
+	Optional   *testNoDefaults        `json:"optional"`
+	Replicas   []testReplica          `json:"replicas"`
+	ReplicaMap map[string]testReplica `json:"replica_map"`
+	Recursive  testRecursive          `json:"recursive"`
+}
+
+func TestWriteDefaults(t *testing.T) {
+	t.Parallel()
+
+	tree := &Node{
+		Children: map[string]*Node{
+			"debug": {Value: "false"},
+			"replicas": {
+				Children: map[string]*Node{
+					"0": {Children: map[string]*Node{"host": {Value: "replica0"}}},
+					"1": {Children: map[string]*Node{"host": {Value: "replica1"}, "port": {Value: "6432"}}},
+				},
+			},
+			"replica_map": {
+				Children: map[string]*Node{
+					"east": {Children: map[string]*Node{"host": {Value: "east"}}},
+				},
+			},
+		},
+	}
+
+	destination := testDefaultsStruct{Host: "preset"}
+	errors := tree.Write(reflect.ValueOf(&destination))
+	require.False(t, errors.Present(), errors.Error())
+
+	retries := 3
+	expected := testDefaultsStruct{
+		PoolSize: 10,
+		Host:     "preset",
+		Debug:    false,
+		Timeout:  30 * time.Second,
+		Retries:  &retries,
+		Primary:  testReplica{Port: 5432},
+		Fallback: &testReplica{Port: 5432},
+		Replicas: []testReplica{
+			{Host: "replica0", Port: 5432},
+			{Host: "replica1", Port: 6432},
+		},
+		ReplicaMap: map[string]testReplica{"east": {Host: "east", Port: 5432}},
+		Recursive:  testRecursive{Name: "node"},
+	}
+	assert.Equal(t, expected, destination)
+}
+
+func TestWriteDefaultsErrors(t *testing.T) {
+	t.Parallel()
+
+	type badDefaults struct {
+		PoolSize int `json:"pool_size" default:"ten"`
+		Nested   struct {
+			Timeout time.Duration `json:"timeout" default:"soon"`
+		} `json:"nested"`
+	}
+
+	var destination badDefaults
+	errors := (&Node{Children: map[string]*Node{}}).Write(reflect.ValueOf(&destination))
+
+	expected := []expectedError{
+		{
+			"pool_size", "PoolSize", "ten", ErrParse,
+			`invalid default value: cannot read int param value: strconv.ParseInt: parsing "ten": invalid syntax`,
+		},
+		{
+			"nested/timeout", "Nested.Timeout", "soon", ErrParse,
+			`invalid default value: cannot read time.Duration param value: time: invalid duration "soon"`,
+		},
+	}
+	assert.ElementsMatch(t, expected, expectedErrors(errors))
+}
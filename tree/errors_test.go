@@ -1,12 +1,32 @@
 package tree
 
 import (
+	"errors"
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// The comparable part of WriteError.
+type expectedError struct {
+	path      string
+	fieldPath string
+	value     string
+	kind      error
+	msg       string
+}
+
+func expectedErrors(errs WriteErrors) []expectedError {
+	result := make([]expectedError, 0, len(errs.errors))
+	for _, err := range errs.errors {
+		result = append(result, expectedError{err.Path, err.FieldPath, err.Value, err.Kind, err.msg})
+	}
+	return result
+}
+
 func TestAllKindsOfErrors(t *testing.T) {
 	t.Parallel()
 
@@ -47,82 +67,98 @@ func TestAllKindsOfErrors(t *testing.T) {
 
 	errors := tree.Write(reflect.ValueOf(&destination))
 
-	expectedErrors := []writeError{
+	expected := []expectedError{
 		{
-			msg:         `cannot read int param value: strconv.ParseInt: parsing "not an int": invalid syntax`,
-			path:        "int",
-			isPathError: false,
+			"int", "Int", "not an int", ErrParse,
+			`cannot read int param value: strconv.ParseInt: parsing "not an int": invalid syntax`,
 		},
 		{
-			msg:         "cannot read bool param value (must be true or false)",
-			path:        "bool",
-			isPathError: false,
+			"bool", "Bool", "yes", ErrParse,
+			"cannot read bool param value (must be true or false)",
 		},
 		{
-			msg:         `cannot read int32 param value: strconv.ParseInt: parsing "1000000000000": value out of range`,
-			path:        "int32",
-			isPathError: false,
+			"int32", "Int32", "1000000000000", ErrParse,
+			`cannot read int32 param value: strconv.ParseInt: parsing "1000000000000": value out of range`,
 		},
 		{
-			msg:         `cannot read uint16 param value: strconv.ParseUint: parsing "-1": invalid syntax`,
-			path:        "uint",
-			isPathError: false,
+			"uint", "Uint", "-1", ErrParse,
+			`cannot read uint16 param value: strconv.ParseUint: parsing "-1": invalid syntax`,
 		},
 		{
-			msg:         "cannot write param: config key is of unsupported type complex64",
-			path:        "complex",
-			isPathError: false,
+			"complex", "Complex64", "unsupported type", ErrUnsupportedType,
+			"cannot write param: config key is of unsupported type complex64",
 		},
 		{
-			msg:         `cannot read int param value: strconv.ParseInt: parsing "bad int": invalid syntax`,
-			path:        "intmap/foo",
-			isPathError: false,
+			"intmap/foo", `IntMap["foo"]`, "bad int", ErrParse,
+			`cannot read int param value: strconv.ParseInt: parsing "bad int": invalid syntax`,
 		},
 		{
-			msg:         `cannot read int param value: strconv.ParseInt: parsing "bad int": invalid syntax`,
-			path:        "intslice/0",
-			isPathError: false,
+			"intslice/0", "IntSlice[0]", "bad int", ErrParse,
+			`cannot read int param value: strconv.ParseInt: parsing "bad int": invalid syntax`,
 		},
 		{
-			msg:         "not a numeric index",
-			path:        "intslice/foo",
-			isPathError: true,
+			"intslice/foo", "IntSlice", "", ErrBadIndex,
+			"not a numeric index",
 		},
 		{
-			msg:         "ignoring self value of key that has child keys",
-			path:        "nested",
-			isPathError: true,
+			"nested", "Nested", "ignored", ErrIgnoredValue,
+			"ignoring self value of key that has child keys",
 		},
 		{
-			msg:         "unknown field",
-			path:        "nested/bad_field",
-			isPathError: true,
+			"nested/bad_field", "Nested", "", ErrUnknownField,
+			"unknown field",
 		},
 		{
-			msg:         "can only write to maps with string keys",
-			path:        "badmap",
-			isPathError: false,
+			"badmap", "BadMap", "", ErrUnsupportedType,
+			"can only write to maps with string keys",
 		},
 	}
 
-	assert.ElementsMatch(t, expectedErrors, errors.errors)
+	assert.ElementsMatch(t, expected, expectedErrors(errors))
+}
+
+func TestErrorsCanBeInspected(t *testing.T) {
+	t.Parallel()
+
+	tree := &Node{
+		Children: map[string]*Node{
+			"int":     {Value: "not an int"},
+			"unknown": {Value: "foo"},
+		},
+	}
+
+	var destination testStructType
+	writeErrors := tree.Write(reflect.ValueOf(&destination))
+	err := writeErrors.Join()
+
+	var writeError *WriteError
+	require.ErrorAs(t, err, &writeError)
+	assert.ErrorIs(t, err, ErrParse)
+	assert.ErrorIs(t, err, ErrUnknownField)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	assert.NotErrorIs(t, err, ErrBadIndex)
+	assert.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 2) //nolint:forcetypeassert,errorlint
+
+	for _, writeError := range writeErrors.Errors() {
+		if errors.Is(writeError, ErrParse) {
+			assert.Equal(t, "int", writeError.Path)
+			assert.Equal(t, "Int", writeError.FieldPath)
+			assert.Equal(t, "not an int", writeError.Value)
+			assert.False(t, writeError.Warning())
+		} else {
+			assert.Equal(t, "unknown", writeError.Path)
+			assert.True(t, writeError.Warning())
+		}
+	}
 }
 
 func TestJoinWarnings(t *testing.T) {
 	t.Parallel()
 
 	warnings := WriteErrors{
-		errors: []writeError{
-			{
-				"warning for foo",
-				"foo",
-				true,
-			},
-			{
-				"warning for bar",
-				"bar",
-				true,
-			},
+		errors: []WriteError{
+			{Path: "foo", Kind: ErrUnknownField, msg: "warning for foo"},
+			{Path: "bar", Kind: ErrBadIndex, msg: "warning for bar"},
 		},
 	}
 
@@ -136,17 +172,9 @@ func TestJoinErrors(t *testing.T) {
 	t.Parallel()
 
 	errors := WriteErrors{
-		errors: []writeError{
-			{
-				"error for foo",
-				"foo",
-				false,
-			},
-			{
-				"warning for bar",
-				"bar",
-				true,
-			},
+		errors: []WriteError{
+			{Path: "foo", Kind: ErrParse, msg: "error for foo"},
+			{Path: "bar", Kind: ErrUnknownField, msg: "warning for bar"},
 		},
 	}
 
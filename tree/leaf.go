@@ -1,14 +1,59 @@
 package tree
 
 import (
+	"encoding"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
+	"time"
+
+	"github.com/railsware/go-global/v2/utils"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
 )
 
-func (paramTree Node) writeLeafValue(destination reflect.Value) WriteErrors {
+func (paramTree Node) writeLeafValue(destination reflect.Value, fieldTag utils.FieldTag) WriteErrors {
 	if !destination.CanSet() {
-		return newWriteErrors("value is not writable")
+		return newWriteErrors(WriteError{Value: paramTree.Value, Kind: ErrNotWritable, msg: "value is not writable"})
+	}
+
+	if decode, ok := lookupDecoder(destination.Type()); ok {
+		return writeDecoded(paramTree.Value, destination, decode)
+	}
+
+	if destination.Kind() == reflect.Ptr {
+		if !destination.IsNil() {
+			return paramTree.writeLeafValue(destination.Elem(), fieldTag)
+		}
+		// only keep the new value if it was written successfully
+		pointer := reflect.New(destination.Type().Elem())
+		errors := paramTree.writeLeafValue(pointer.Elem(), fieldTag)
+		if !errors.Present() {
+			destination.Set(pointer)
+		}
+		return errors
+	}
+
+	switch destination.Type() {
+	case durationType:
+		return writeDuration(paramTree.Value, destination, fieldTag.Unit)
+	case timeType:
+		return writeTime(paramTree.Value, destination, fieldTag.Layout)
+	}
+
+	if destination.CanAddr() {
+		switch pointerType := destination.Addr().Type(); {
+		case pointerType.Implements(textUnmarshalerType):
+			return writeDecoded(paramTree.Value, destination, decodeText)
+		case pointerType.Implements(jsonUnmarshalerType):
+			return writeDecoded(paramTree.Value, destination, decodeJSON)
+		}
 	}
 
 	switch destination.Kind() { //nolint:exhaustive // we don't cover all types
@@ -24,15 +69,27 @@ func (paramTree Node) writeLeafValue(destination reflect.Value) WriteErrors {
 	case reflect.Bool:
 		return writeBool(paramTree.Value, destination)
 	default:
-		err := fmt.Sprintf("cannot write param: config key is of unsupported type %s", destination.Kind())
-		return newWriteErrors(err)
+		return newWriteErrors(WriteError{
+			Value: paramTree.Value,
+			Kind:  ErrUnsupportedType,
+			msg:   fmt.Sprintf("cannot write param: config key is of unsupported type %s", destination.Kind()),
+		})
 	}
 }
 
+func newParseErrors(source string, destination reflect.Value, err error) WriteErrors {
+	return newWriteErrors(WriteError{
+		Value: source,
+		Kind:  ErrParse,
+		Err:   err,
+		msg:   fmt.Sprintf("cannot read %v param value: %v", destination.Kind(), err),
+	})
+}
+
 func writeInt(source string, destination reflect.Value) WriteErrors {
 	intval, err := strconv.ParseInt(source, 10, destination.Type().Bits())
 	if err != nil {
-		return newWriteErrors(fmt.Sprintf("cannot read %v param value: %v", destination.Kind(), err))
+		return newParseErrors(source, destination, err)
 	}
 	destination.SetInt(intval)
 	return WriteErrors{}
@@ -41,7 +98,7 @@ func writeInt(source string, destination reflect.Value) WriteErrors {
 func writeUint(source string, destination reflect.Value) WriteErrors {
 	uintval, err := strconv.ParseUint(source, 10, destination.Type().Bits())
 	if err != nil {
-		return newWriteErrors(fmt.Sprintf("cannot read %v param value: %v", destination.Kind(), err))
+		return newParseErrors(source, destination, err)
 	}
 	destination.SetUint(uintval)
 	return WriteErrors{}
@@ -50,7 +107,7 @@ func writeUint(source string, destination reflect.Value) WriteErrors {
 func writeFloat(source string, destination reflect.Value) WriteErrors {
 	floatval, err := strconv.ParseFloat(source, destination.Type().Bits())
 	if err != nil {
-		return newWriteErrors(fmt.Sprintf("cannot read %v param value: %v", destination.Kind(), err))
+		return newParseErrors(source, destination, err)
 	}
 	destination.SetFloat(floatval)
 	return WriteErrors{}
@@ -63,7 +120,94 @@ func writeBool(source string, destination reflect.Value) WriteErrors {
 	case "false":
 		destination.SetBool(false)
 	default:
-		return newWriteErrors("cannot read bool param value (must be true or false)")
+		return newWriteErrors(WriteError{
+			Value: source,
+			Kind:  ErrParse,
+			msg:   "cannot read bool param value (must be true or false)",
+		})
+	}
+	return WriteErrors{}
+}
+
+// Returns true if values of the type are written from a single param value, even if the type is a struct.
+// IsDecodable reports whether values of the type are written from a single param, rather than from its children:
+// types with a registered decoder, time.Duration, time.Time and unmarshalers.
+func IsDecodable(valueType reflect.Type) bool {
+	return isDecodable(valueType)
+}
+
+func isDecodable(valueType reflect.Type) bool {
+	if _, ok := lookupDecoder(valueType); ok {
+		return true
+	}
+	if valueType == durationType || valueType == timeType {
+		return true
+	}
+	pointerType := reflect.PointerTo(valueType)
+	return pointerType.Implements(textUnmarshalerType) || pointerType.Implements(jsonUnmarshalerType)
+}
+
+// Accepts durations like "1m30s", or bare integers if unit is set.
+func writeDuration(source string, destination reflect.Value, unit time.Duration) WriteErrors {
+	duration, err := time.ParseDuration(source)
+	if err != nil && unit != 0 {
+		if count, intErr := strconv.ParseInt(source, 10, 64); intErr == nil {
+			duration, err = time.Duration(count)*unit, nil
+		}
+	}
+	if err != nil {
+		return newWriteErrors(WriteError{
+			Value: source,
+			Kind:  ErrParse,
+			Err:   err,
+			msg:   fmt.Sprintf("cannot read time.Duration param value: %v", err),
+		})
+	}
+	destination.SetInt(int64(duration))
+	return WriteErrors{}
+}
+
+func writeTime(source string, destination reflect.Value, layout string) WriteErrors {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	parsedTime, err := time.Parse(layout, source)
+	if err != nil {
+		return newWriteErrors(WriteError{
+			Value: source,
+			Kind:  ErrParse,
+			Err:   err,
+			msg:   fmt.Sprintf("cannot read time.Time param value (layout %q): %v", layout, err),
+		})
+	}
+	destination.Set(reflect.ValueOf(parsedTime))
+	return WriteErrors{}
+}
+
+func writeDecoded(source string, destination reflect.Value, decode DecodeFunc) WriteErrors {
+	if err := decode(source, destination); err != nil {
+		return newWriteErrors(WriteError{
+			Value: source,
+			Kind:  ErrParse,
+			Err:   err,
+			msg:   fmt.Sprintf("cannot read %v param value: %v", destination.Type(), err),
+		})
 	}
 	return WriteErrors{}
 }
+
+func decodeText(source string, destination reflect.Value) error {
+	return destination.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(source)) //nolint:forcetypeassert
+}
+
+// Passes the value as is if it is valid JSON, e.g. a number or an object, otherwise as a JSON string.
+func decodeJSON(source string, destination reflect.Value) error {
+	data := []byte(source)
+	if !json.Valid(data) {
+		var err error
+		if data, err = json.Marshal(source); err != nil {
+			return err
+		}
+	}
+	return destination.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(data) //nolint:forcetypeassert
+}
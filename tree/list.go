@@ -0,0 +1,47 @@
+package tree
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/railsware/go-global/v2/utils"
+)
+
+const (
+	// StringListType is the Origin.Type of params holding comma-separated lists, as in Parameter Store.
+	StringListType = "StringList"
+
+	stringListSeparator = ","
+)
+
+// Returns the separator to split the leaf value with, if it is a list that should be written into a slice or array:
+// either the field is tagged with `split:`, or the param is a StringList.
+func (paramTree Node) listSeparator(destinationType reflect.Type, fieldTag utils.FieldTag) (string, bool) {
+	if destinationType.Kind() == reflect.Ptr {
+		destinationType = destinationType.Elem()
+	}
+	if (destinationType.Kind() != reflect.Slice && destinationType.Kind() != reflect.Array) ||
+		isDecodable(destinationType) {
+		return "", false
+	}
+	if fieldTag.Split != "" {
+		return fieldTag.Split, true
+	}
+	if paramTree.Origin != nil && paramTree.Origin.Type == StringListType {
+		return stringListSeparator, true
+	}
+	return "", false
+}
+
+// Converts the leaf into a node with an indexed child per list element. An empty value is an empty list.
+func (paramTree Node) splitList(separator string) Node {
+	list := Node{Children: make(map[string]*Node)}
+	if paramTree.Value == "" {
+		return list
+	}
+	for index, element := range strings.Split(paramTree.Value, separator) {
+		list.Children[strconv.Itoa(index)] = &Node{Value: element, Origin: paramTree.Origin}
+	}
+	return list
+}
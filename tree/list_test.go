@@ -0,0 +1,79 @@
+package tree
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteLists(t *testing.T) {
+	t.Parallel()
+
+	stringList := &global.Origin{Name: "/app/hosts", Type: StringListType}
+
+	var destination struct {
+		Hosts    []string         `json:"hosts"`
+		Ports    []int            `json:"ports" split:";"`
+		Retries  *[]time.Duration `json:"retries" split:"," unit:"ms"`
+		Empty    []string         `json:"empty" split:","`
+		Defaults []string         `json:"defaults" split:"," default:"a,b"`
+		Plain    string           `json:"plain"`
+		IP       net.IP           `json:"ip"`
+	}
+	tree := &Node{
+		Children: map[string]*Node{
+			"hosts":   {Value: "a.internal,b.internal", Origin: stringList},
+			"ports":   {Value: "80;443"},
+			"retries": {Value: "100,2s"},
+			"empty":   {Value: ""},
+			"plain":   {Value: "x,y", Origin: stringList},
+			"ip":      {Value: "10.0.0.1", Origin: stringList},
+		},
+	}
+
+	errors := tree.Write(reflect.ValueOf(&destination))
+	require.False(t, errors.Present(), errors.Error())
+
+	assert.Equal(t, []string{"a.internal", "b.internal"}, destination.Hosts)
+	assert.Equal(t, []int{80, 443}, destination.Ports)
+	require.NotNil(t, destination.Retries)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 2 * time.Second}, *destination.Retries)
+	assert.Empty(t, destination.Empty)
+	assert.Equal(t, []string{"a", "b"}, destination.Defaults)
+	assert.Equal(t, "x,y", destination.Plain)
+	assert.Equal(t, net.ParseIP("10.0.0.1"), destination.IP)
+}
+
+func TestWriteListsErrors(t *testing.T) {
+	t.Parallel()
+
+	var destination struct {
+		Ports []int    `json:"ports" split:","`
+		Hosts []string `json:"hosts"`
+	}
+	tree := &Node{
+		Children: map[string]*Node{
+			"ports": {Value: "80,http"},
+			"hosts": {Value: "a,b"},
+		},
+	}
+
+	errors := tree.Write(reflect.ValueOf(&destination))
+	require.Len(t, errors.Errors(), 2)
+	for _, err := range errors.Errors() {
+		switch err.Path {
+		case "ports/1":
+			assert.ErrorIs(t, err, ErrParse)
+			assert.Equal(t, "Ports[1]", err.FieldPath)
+		case "hosts":
+			assert.ErrorIs(t, err, ErrUnsupportedType, "not a StringList and no split tag")
+		default:
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+}
@@ -1,12 +1,13 @@
 package tree
 
 import (
+	"fmt"
 	"reflect"
 )
 
-func (paramTree Node) writeIntoMap(destination reflect.Value) WriteErrors {
+func (paramTree Node) writeIntoMap(destination reflect.Value, state writeState) WriteErrors {
 	if destination.Type().Key().Kind() != reflect.String {
-		return newWriteErrors("can only write to maps with string keys")
+		return newWriteErrors(WriteError{Kind: ErrUnsupportedType, msg: "can only write to maps with string keys"})
 	}
 	var errors WriteErrors
 	if destination.Type().Elem().Kind() == reflect.Ptr {
@@ -17,7 +18,8 @@ func (paramTree Node) writeIntoMap(destination reflect.Value) WriteErrors {
 				pointer = reflect.New(destination.Type().Elem().Elem())
 				destination.SetMapIndex(reflect.ValueOf(key), pointer)
 			}
-			errors.mergeChildErrors(key, childTree.Write(pointer.Elem()))
+			fieldSegment := fmt.Sprintf("[%q]", key)
+			errors.mergeChildErrors(key, fieldSegment, childTree.write(pointer.Elem(), state.child(fieldSegment)))
 		}
 	} else {
 		// need to create a copy of the value and write it into the map
@@ -27,7 +29,8 @@ func (paramTree Node) writeIntoMap(destination reflect.Value) WriteErrors {
 			if oldValue.IsValid() {
 				newValue.Elem().Set(oldValue)
 			}
-			errors.mergeChildErrors(key, childTree.Write(newValue.Elem()))
+			fieldSegment := fmt.Sprintf("[%q]", key)
+			errors.mergeChildErrors(key, fieldSegment, childTree.write(newValue.Elem(), state.child(fieldSegment)))
 			destination.SetMapIndex(reflect.ValueOf(key), newValue.Elem())
 		}
 	}
@@ -0,0 +1,31 @@
+package tree
+
+// Merge deep-merges two parameter trees and returns the result.
+// Values from override win: a leaf or a sequence replaces whatever is in base, other children are merged recursively.
+// Neither of the arguments is modified, but the result may share nodes with them.
+func Merge(base, override *Node) *Node {
+	if base == nil {
+		return override
+	}
+	if override == nil {
+		return base
+	}
+	if override.Children == nil || base.Children == nil || override.Sequence {
+		return override
+	}
+
+	merged := &Node{
+		Value:    base.Value,
+		Children: make(map[string]*Node, len(base.Children)),
+	}
+	if override.Value != "" {
+		merged.Value = override.Value
+	}
+	for key, child := range base.Children {
+		merged.Children[key] = child
+	}
+	for key, child := range override.Children {
+		merged.Children[key] = Merge(merged.Children[key], child)
+	}
+	return merged
+}
@@ -0,0 +1,79 @@
+package tree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	base := &Node{
+		Children: map[string]*Node{
+			"str": {Value: "base"},
+			"int": {Value: "1"},
+			"nested": {
+				Children: map[string]*Node{
+					"str": {Value: "nested_base"},
+					"int": {Value: "2"},
+				},
+			},
+			"replaced": {
+				Children: map[string]*Node{
+					"0": {Value: "base"},
+				},
+			},
+		},
+	}
+
+	override := &Node{
+		Children: map[string]*Node{
+			"str": {Value: "override"},
+			"nested": {
+				Children: map[string]*Node{
+					"str": {Value: "nested_override"},
+				},
+			},
+			"replaced": {Value: "leaf"},
+			"new":      {Value: "new"},
+		},
+	}
+
+	expectedTree := &Node{
+		Children: map[string]*Node{
+			"str": {Value: "override"},
+			"int": {Value: "1"},
+			"nested": {
+				Children: map[string]*Node{
+					"str": {Value: "nested_override"},
+					"int": {Value: "2"},
+				},
+			},
+			"replaced": {Value: "leaf"},
+			"new":      {Value: "new"},
+		},
+	}
+
+	assert.Equal(t, expectedTree, Merge(base, override))
+	assert.Equal(t, "base", base.Children["str"].Value, "base is not modified")
+	assert.Equal(t, base, Merge(base, nil))
+	assert.Equal(t, override, Merge(nil, override))
+}
+
+func TestMergeReplacesSequences(t *testing.T) {
+	t.Parallel()
+
+	base := &Node{
+		Children: map[string]*Node{
+			"urls": {Children: map[string]*Node{"0": {Value: "a"}, "1": {Value: "b"}}, Sequence: true},
+		},
+	}
+	override := &Node{
+		Children: map[string]*Node{
+			"urls": {Children: map[string]*Node{"0": {Value: "x"}}, Sequence: true},
+		},
+	}
+
+	assert.Equal(t, override.Children["urls"], Merge(base, override).Children["urls"])
+}
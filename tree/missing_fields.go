@@ -0,0 +1,115 @@
+package tree
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/utils"
+)
+
+// Handles zero-valued fields of the struct that were not supplied by the tree, recursing into nested structs:
+//   - writes values of `default:` tags
+//   - reports required fields as missing
+//
+// visiting holds the struct types being processed up the stack,
+// so that nil pointers of recursive types are not expanded forever.
+// Returns true if any default value was written.
+func writeMissingFields(
+	destination reflect.Value,
+	suppliedFields map[string]bool,
+	state writeState,
+	visiting map[reflect.Type]bool,
+) (bool, WriteErrors) {
+	var errors WriteErrors
+	written := false
+
+	visiting[destination.Type()] = true
+	defer delete(visiting, destination.Type())
+
+	for _, structField := range reflect.VisibleFields(destination.Type()) {
+		if !structField.IsExported() || structField.Anonymous || suppliedFields[fieldKey(structField)] {
+			continue
+		}
+		field, err := destination.FieldByIndexErr(structField.Index)
+		if err != nil {
+			// promoted through a nil embedded pointer
+			continue
+		}
+
+		fieldWritten, fieldErrors := writeMissingField(field, structField, state.child(structField.Name), visiting)
+		written = written || fieldWritten
+		errors.mergeChildErrors(utils.ParamName(structField), structField.Name, fieldErrors)
+	}
+
+	return written, errors
+}
+
+func writeMissingField(
+	field reflect.Value,
+	structField reflect.StructField,
+	state writeState,
+	visiting map[reflect.Type]bool,
+) (bool, WriteErrors) {
+	fieldTag, err := utils.ParseFieldTag(structField)
+	if err != nil {
+		return false, newWriteErrors(WriteError{Kind: ErrInvalidTag, Err: err, msg: err.Error()})
+	}
+
+	if !field.IsZero() {
+		if field.Kind() == reflect.Ptr {
+			field = field.Elem()
+		}
+		if field.Kind() == reflect.Struct && !isDecodable(field.Type()) {
+			return writeMissingFields(field, nil, state, visiting)
+		}
+		return false, WriteErrors{}
+	}
+
+	if fieldTag.HasDefault {
+		defaultValue := Node{Value: fieldTag.Default, Origin: &global.Origin{Backend: global.BackendDefault}}
+		errors := defaultValue.write(field, state.withFieldTag(fieldTag))
+		for index := range errors.errors {
+			errors.errors[index].msg = fmt.Sprintf("invalid default value: %s", errors.errors[index].msg)
+		}
+		return !errors.Present(), errors
+	}
+
+	var errors WriteErrors
+	if fieldTag.Required {
+		errors.append(WriteError{Kind: ErrMissing})
+	}
+
+	structType := field.Type()
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct || isDecodable(structType) {
+		if state.options.RequireAll && !fieldTag.Required {
+			errors.append(WriteError{Kind: ErrMissing})
+		}
+		return false, errors
+	}
+
+	if field.Kind() != reflect.Ptr {
+		written, nestedErrors := writeMissingFields(field, nil, state, visiting)
+		errors.merge(nestedErrors)
+		return written, errors
+	}
+	if visiting[structType] {
+		return false, errors
+	}
+	// only keep the new struct if it got any defaults
+	pointer := reflect.New(structType)
+	written, nestedErrors := writeMissingFields(pointer.Elem(), nil, state, visiting)
+	if written {
+		field.Set(pointer)
+	}
+	errors.merge(nestedErrors)
+	return written, errors
+}
+
+// Identifies the field within the struct, including promoted fields of embedded structs.
+func fieldKey(structField reflect.StructField) string {
+	return fmt.Sprint(structField.Index)
+}
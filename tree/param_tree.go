@@ -3,23 +3,46 @@ package tree
 import (
 	"fmt"
 	"reflect"
+
+	"github.com/railsware/go-global/v2"
 )
 
 // One node of the parameter tree.
 type Node struct {
 	Value    string
 	Children map[string]*Node
+	// Origin of the value, if known.
+	Origin *global.Origin
+	// Sequence is set if the children are the elements of a list, e.g. a YAML sequence.
+	// Merge replaces a sequence as a whole instead of merging it element by element.
+	Sequence bool
 }
 
 func (paramTree Node) Write(destination reflect.Value) WriteErrors {
+	return paramTree.WriteWithOptions(destination, WriteOptions{})
+}
+
+func (paramTree Node) write(destination reflect.Value, state writeState) WriteErrors {
 	if paramTree.Children == nil {
-		return paramTree.writeLeafValue(destination)
+		if separator, ok := paramTree.listSeparator(destination.Type(), state.fieldTag); ok {
+			return paramTree.splitList(separator).write(destination, state)
+		}
+		errors := paramTree.writeLeafValue(destination, state.fieldTag)
+		errors.setOrigin(paramTree.Origin)
+		if !errors.Present() {
+			state.recordOrigin(paramTree.Origin)
+		}
+		return errors
 	}
 
 	var errors WriteErrors
 
 	if paramTree.Value != "" {
-		errors.append(writeError{"ignoring self value of key that has child keys", "", true})
+		errors.append(WriteError{
+			Value: paramTree.Value,
+			Kind:  ErrIgnoredValue,
+			msg:   "ignoring self value of key that has child keys",
+		})
 	}
 
 	if destination.Kind() == reflect.Ptr {
@@ -31,17 +54,61 @@ func (paramTree Node) Write(destination reflect.Value) WriteErrors {
 
 	switch destination.Kind() { //nolint:exhaustive // not covering all possible types
 	case reflect.Struct:
-		errors.merge(paramTree.writeIntoStruct(destination))
+		errors.merge(paramTree.writeIntoStruct(destination, state))
 	case reflect.Map:
 		if destination.IsNil() {
 			destination.Set(reflect.MakeMap(destination.Type()))
 		}
-		errors.merge(paramTree.writeIntoMap(destination))
+		errors.merge(paramTree.writeIntoMap(destination, state))
 	case reflect.Slice:
-		errors.merge(paramTree.writeIntoSlice(destination))
+		errors.merge(paramTree.writeIntoSlice(destination, state))
+	case reflect.Array:
+		errors.merge(paramTree.writeIntoArray(destination, state))
 	default:
-		errors.append(writeError{fmt.Sprintf("unhandleable destination type: %v", destination.Kind()), "", false})
+		errors.append(WriteError{
+			Kind: ErrUnsupportedType,
+			msg:  fmt.Sprintf("unhandleable destination type: %v", destination.Kind()),
+		})
 	}
 
 	return errors
 }
+
+// Equal reports whether both trees have the same values and structure,
+// and their values come from params of the same type and version, see global.Origin.
+// Other details of origins are not compared.
+func (paramTree *Node) Equal(other *Node) bool {
+	if paramTree == nil || other == nil {
+		return paramTree == other
+	}
+	if paramTree.Value != other.Value || len(paramTree.Children) != len(other.Children) {
+		return false
+	}
+	if paramTree.originType() != other.originType() || paramTree.originVersion() != other.originVersion() {
+		return false
+	}
+	if (paramTree.Children == nil) != (other.Children == nil) {
+		return false
+	}
+	for key, child := range paramTree.Children {
+		otherChild, ok := other.Children[key]
+		if !ok || !child.Equal(otherChild) {
+			return false
+		}
+	}
+	return true
+}
+
+func (paramTree *Node) originType() string {
+	if paramTree.Origin == nil {
+		return ""
+	}
+	return paramTree.Origin.Type
+}
+
+func (paramTree *Node) originVersion() string {
+	if paramTree.Origin == nil {
+		return ""
+	}
+	return paramTree.Origin.Version
+}
@@ -0,0 +1,54 @@
+package tree
+
+import (
+	"testing"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteConfigRecordsProvenance(t *testing.T) {
+	t.Parallel()
+
+	origin := func(name string) *global.Origin {
+		return &global.Origin{Backend: global.BackendYAML, Name: name}
+	}
+
+	var config struct {
+		Database struct {
+			PoolSize int      `json:"pool_size"`
+			Timeout  string   `json:"timeout" default:"30s"`
+			URLs     []string `json:"urls"`
+		} `json:"database"`
+		Limits map[string]int `json:"limits"`
+		Broken int            `json:"broken"`
+	}
+	tree := &Node{
+		Children: map[string]*Node{
+			"database": {
+				Children: map[string]*Node{
+					"pool_size": {Value: "10", Origin: origin("pool_size")},
+					"urls":      {Children: map[string]*Node{"0": {Value: "postgres://primary", Origin: origin("url")}}},
+				},
+			},
+			"limits": {Children: map[string]*Node{"api": {Value: "5", Origin: origin("limit")}}},
+			"broken": {Value: "not a number", Origin: origin("broken")},
+		},
+	}
+
+	provenance := global.Provenance{"Other": *origin("other")}
+	err := tree.WriteConfig(&config, WriteOptions{Provenance: provenance})
+	assert.ErrorIs(t, err, ErrParse)
+
+	assert.Equal(
+		t,
+		global.Provenance{
+			"Database.PoolSize": *origin("pool_size"),
+			"Database.Timeout":  {Backend: global.BackendDefault},
+			"Database.URLs[0]":  *origin("url"),
+			`Limits["api"]`:     *origin("limit"),
+			"Other":             *origin("other"),
+		},
+		provenance,
+	)
+}
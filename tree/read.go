@@ -0,0 +1,206 @@
+package tree
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/utils"
+)
+
+// SecureStringType is the Origin.Type of encrypted params, as in Parameter Store.
+const SecureStringType = "SecureString"
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// ReadConfig reflects config into a tree, the inverse of WriteConfig.
+//   - config must be a pointer to a struct.
+//   - Children are named like params: by the `global:` tag, the `json:` tag or the field name.
+//   - Values are formatted so that Write parses them back, e.g. durations as "1m30s" and times with the `layout:` tag.
+//     Slices of fields tagged with `split:` are joined into a single value.
+//   - Nil pointers, slices and maps, and empty strings are left out.
+//   - Values of fields tagged `global:",sensitive"`, and of everything nested in them, get an Origin with the
+//     SecureString type.
+func ReadConfig(config interface{}) (*Node, global.Error) {
+	reflectedConfig, err := utils.ReflectConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var errors WriteErrors
+	configTree := readValue(reflectedConfig, utils.FieldTag{}, &errors)
+	if errors.Present() {
+		return nil, errors.Join()
+	}
+	if configTree == nil || configTree.Children == nil {
+		return &Node{Children: map[string]*Node{}}, nil
+	}
+	return configTree, nil
+}
+
+// Converts the value into a node, or returns nil if there is nothing to store.
+// Errors are collected with paths relative to the value.
+func readValue(value reflect.Value, fieldTag utils.FieldTag, errors *WriteErrors) *Node {
+	if value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		return readValue(value.Elem(), fieldTag, errors)
+	}
+
+	if leafValue, ok := readLeafValue(value, fieldTag); ok {
+		return readLeaf(leafValue, fieldTag)
+	}
+
+	node := &Node{Children: make(map[string]*Node)}
+	switch value.Kind() { //nolint:exhaustive // leaves are handled above
+	case reflect.Struct:
+		for _, structField := range reflect.VisibleFields(value.Type()) {
+			if !structField.IsExported() || structField.Anonymous || utils.TagName(structField, "json") == "-" {
+				continue
+			}
+			field, err := value.FieldByIndexErr(structField.Index)
+			if err != nil {
+				// promoted through a nil embedded pointer
+				continue
+			}
+			childName := utils.ParamName(structField)
+			childFieldTag, err := utils.ParseFieldTag(structField)
+			if err != nil {
+				errors.append(WriteError{
+					Path: childName, FieldPath: structField.Name, Kind: ErrInvalidTag, Err: err, msg: err.Error(),
+				})
+				continue
+			}
+			// everything nested in a sensitive field is sensitive too
+			childFieldTag.Sensitive = childFieldTag.Sensitive || fieldTag.Sensitive
+			var childErrors WriteErrors
+			node.addChild(childName, readValue(field, childFieldTag, &childErrors))
+			errors.mergeChildErrors(childName, structField.Name, childErrors)
+		}
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+		iterator := value.MapRange()
+		for iterator.Next() {
+			key := fmt.Sprint(iterator.Key().Interface())
+			var childErrors WriteErrors
+			node.addChild(key, readValue(iterator.Value(), fieldTag, &childErrors))
+			errors.mergeChildErrors(key, fmt.Sprintf("[%q]", key), childErrors)
+		}
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil
+		}
+		for index := 0; index < value.Len(); index++ {
+			var childErrors WriteErrors
+			node.addChild(strconv.Itoa(index), readValue(value.Index(index), fieldTag, &childErrors))
+			errors.mergeChildErrors(strconv.Itoa(index), fmt.Sprintf("[%d]", index), childErrors)
+		}
+		if fieldTag.Split != "" {
+			return joinList(node, value.Len(), fieldTag)
+		}
+	default:
+		errors.append(WriteError{
+			Kind: ErrUnsupportedType,
+			msg:  fmt.Sprintf("cannot read config key of unsupported type %s", value.Kind()),
+		})
+		return nil
+	}
+	return node
+}
+
+func (paramTree *Node) addChild(name string, child *Node) {
+	if child != nil {
+		paramTree.Children[name] = child
+	}
+}
+
+// Formats a value that Write reads from a single param. Returns false if the value has children.
+func readLeafValue(value reflect.Value, fieldTag utils.FieldTag) (string, bool) {
+	switch value.Type() {
+	case durationType:
+		return value.Interface().(time.Duration).String(), true //nolint:forcetypeassert // checked above
+	case timeType:
+		layout := fieldTag.Layout
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return value.Interface().(time.Time).Format(layout), true //nolint:forcetypeassert // checked above
+	}
+
+	if isDecodable(value.Type()) {
+		pointer := reflect.New(value.Type())
+		pointer.Elem().Set(value)
+		switch {
+		case pointer.Type().Implements(textMarshalerType):
+			text, err := pointer.Interface().(encoding.TextMarshaler).MarshalText() //nolint:forcetypeassert // checked above
+			if err == nil {
+				return string(text), true
+			}
+		case pointer.Type().Implements(jsonMarshalerType):
+			raw, err := pointer.Interface().(json.Marshaler).MarshalJSON() //nolint:forcetypeassert // checked above
+			if err == nil {
+				// JSON strings are stored unquoted, like Write reads them
+				var text string
+				if json.Unmarshal(raw, &text) == nil {
+					return text, true
+				}
+				return string(raw), true
+			}
+		case pointer.Type().Implements(stringerType):
+			return pointer.Interface().(fmt.Stringer).String(), true //nolint:forcetypeassert // checked above
+		}
+		return fmt.Sprint(value.Interface()), true
+	}
+
+	switch value.Kind() { //nolint:exhaustive // other kinds have children or are unsupported
+	case reflect.String:
+		return value.String(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, value.Type().Bits()), true
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), true
+	default:
+		return "", false
+	}
+}
+
+// Joins the elements of the list node with the separator of the `split:` tag.
+func joinList(list *Node, length int, fieldTag utils.FieldTag) *Node {
+	elements := make([]string, 0, length)
+	for index := 0; index < length; index++ {
+		if element, ok := list.Children[strconv.Itoa(index)]; ok && element.Children == nil {
+			elements = append(elements, element.Value)
+		} else {
+			// nested or missing elements cannot be joined
+			return list
+		}
+	}
+	return readLeaf(strings.Join(elements, fieldTag.Split), fieldTag)
+}
+
+func readLeaf(value string, fieldTag utils.FieldTag) *Node {
+	if value == "" {
+		return nil
+	}
+	node := &Node{Value: value}
+	if fieldTag.Sensitive {
+		node.Origin = &global.Origin{Type: SecureStringType}
+	}
+	return node
+}
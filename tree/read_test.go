@@ -0,0 +1,97 @@
+package tree
+
+import (
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type readTestConfig struct {
+	Database struct {
+		Host     string        `json:"host"`
+		Password string        `json:"password" global:",sensitive"`
+		PoolSize int           `json:"pool_size"`
+		Ratio    float64       `json:"ratio"`
+		SSL      bool          `json:"ssl"`
+		Timeout  time.Duration `json:"timeout"`
+		Replicas []string      `json:"replicas"`
+		Ports    []int         `json:"ports" split:","`
+	} `json:"database"`
+	URL      url.URL              `json:"url"`
+	IP       net.IP               `json:"ip"`
+	Date     time.Time            `json:"date" layout:"2006-01-02"`
+	Limits   map[string]uint      `json:"limits"`
+	Empty    string               `json:"empty"`
+	Nil      *string              `json:"nil"`
+	Skipped  string               `json:"-"`
+	Children map[string]*struct{} `json:"children"`
+}
+
+func TestReadConfig(t *testing.T) {
+	t.Parallel()
+
+	var config readTestConfig
+	config.Database.Host = "db.internal"
+	config.Database.Password = "hunter2"
+	config.Database.PoolSize = 10
+	config.Database.Ratio = 0.5
+	config.Database.SSL = true
+	config.Database.Timeout = 90 * time.Second
+	config.Database.Replicas = []string{"r1", "r2"}
+	config.Database.Ports = []int{80, 443}
+	config.URL = url.URL{Scheme: "https", Host: "example.com"}
+	config.IP = net.ParseIP("10.0.0.1")
+	config.Date = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	config.Limits = map[string]uint{"api": 5}
+	config.Skipped = "skipped"
+
+	configTree, err := ReadConfig(&config)
+	require.Nil(t, err)
+
+	expectedTree := &Node{
+		Children: map[string]*Node{
+			"database": {
+				Children: map[string]*Node{
+					"host":      {Value: "db.internal"},
+					"password":  {Value: "hunter2", Origin: &global.Origin{Type: SecureStringType}},
+					"pool_size": {Value: "10"},
+					"ratio":     {Value: "0.5"},
+					"ssl":       {Value: "true"},
+					"timeout":   {Value: "1m30s"},
+					"replicas":  {Children: map[string]*Node{"0": {Value: "r1"}, "1": {Value: "r2"}}},
+					"ports":     {Value: "80,443"},
+				},
+			},
+			"url":    {Value: "https://example.com"},
+			"ip":     {Value: "10.0.0.1"},
+			"date":   {Value: "2024-01-02"},
+			"limits": {Children: map[string]*Node{"api": {Value: "5"}}},
+		},
+	}
+	assert.Equal(t, expectedTree, configTree)
+
+	var readBack readTestConfig
+	assert.Nil(t, configTree.WriteConfig(&readBack, WriteOptions{}))
+	config.Skipped = ""
+	assert.Equal(t, config, readBack)
+}
+
+func TestReadConfigErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := ReadConfig(readTestConfig{})
+	assert.NotNil(t, err)
+
+	var config struct {
+		Callback func() `json:"callback"`
+	}
+	config.Callback = func() {}
+	_, err = ReadConfig(&config)
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+	assert.Contains(t, err.Error(), "callback: cannot read config key of unsupported type func")
+}
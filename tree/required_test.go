@@ -0,0 +1,84 @@
+package tree
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRequiredStruct struct {
+	Host     string `json:"host" global:",required"`
+	PoolSize int    `global:"pool_size,required" default:"10"`
+	Debug    bool   `json:"debug"`
+	Replica  struct {
+		Host string `json:"host" global:",required"`
+	} `json:"replica"`
+	Fallback *struct {
+		Host string `json:"host" global:",required"`
+	} `json:"fallback"`
+	Replicas []struct {
+		Host string `json:"host" global:",required"`
+		Port int    `json:"port"`
+	} `json:"replicas"`
+}
+
+func TestWriteReportsMissingRequiredFields(t *testing.T) {
+	t.Parallel()
+
+	tree := &Node{
+		Children: map[string]*Node{
+			"debug": {Value: "true"},
+			"replicas": {
+				Children: map[string]*Node{
+					"0": {Children: map[string]*Node{"port": {Value: "5432"}}},
+				},
+			},
+		},
+	}
+
+	var destination testRequiredStruct
+	errors := tree.WriteWithOptions(reflect.ValueOf(&destination), WriteOptions{ParamPrefix: "/app/"})
+
+	expected := []expectedError{
+		{"host", "Host", "", ErrMissing, "missing parameter /app/host"},
+		{"replica/host", "Replica.Host", "", ErrMissing, "missing parameter /app/replica/host"},
+		{"fallback/host", "Fallback.Host", "", ErrMissing, "missing parameter /app/fallback/host"},
+		{"replicas/0/host", "Replicas[0].Host", "", ErrMissing, "missing parameter /app/replicas/0/host"},
+	}
+	assert.ElementsMatch(t, expected, expectedErrors(errors))
+	assert.False(t, errors.Warning())
+	assert.Equal(t, 10, destination.PoolSize, "default satisfies required")
+	assert.Nil(t, destination.Fallback)
+}
+
+func TestWriteWithRequireAll(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Host     string            `json:"host"`
+		PoolSize int               `json:"pool_size" default:"10"`
+		Options  map[string]string `json:"options"`
+		Nested   struct {
+			Port int `json:"port"`
+		} `json:"nested"`
+		Preset string `json:"preset"`
+	}
+
+	tree := &Node{Children: map[string]*Node{"host": {Value: "localhost"}}}
+
+	destination := config{Preset: "already set"}
+	errors := tree.WriteWithOptions(reflect.ValueOf(&destination), WriteOptions{RequireAll: true})
+
+	expected := []expectedError{
+		{"options", "Options", "", ErrMissing, "missing parameter options"},
+		{"nested/port", "Nested.Port", "", ErrMissing, "missing parameter nested/port"},
+	}
+	assert.ElementsMatch(t, expected, expectedErrors(errors))
+
+	tree.Children["options"] = &Node{Children: map[string]*Node{"foo": {Value: "bar"}}}
+	tree.Children["nested"] = &Node{Children: map[string]*Node{"port": {Value: "80"}}}
+	errors = tree.WriteWithOptions(reflect.ValueOf(&destination), WriteOptions{RequireAll: true})
+	require.False(t, errors.Present(), errors.Error())
+}
@@ -1,18 +1,19 @@
 package tree
 
 import (
+	"fmt"
 	"reflect"
 	"strconv"
 )
 
-func (paramTree Node) writeIntoSlice(destination reflect.Value) WriteErrors {
+func (paramTree Node) writeIntoSlice(destination reflect.Value, state writeState) WriteErrors {
 	indexedParams := make(map[int]*Node)
 	maxIndex := -1
 	var errors WriteErrors
 	for stringIndex, childTree := range paramTree.Children {
 		index, err := strconv.Atoi(stringIndex)
 		if err != nil || index < 0 {
-			errors.append(writeError{"not a numeric index", stringIndex, true})
+			errors.append(WriteError{Path: stringIndex, Kind: ErrBadIndex, msg: "not a numeric index"})
 			continue
 		}
 		indexedParams[index] = childTree
@@ -30,9 +31,37 @@ func (paramTree Node) writeIntoSlice(destination reflect.Value) WriteErrors {
 		destination.SetLen(maxIndex + 1)
 	}
 	for index, childTree := range indexedParams {
-		childErrors := childTree.Write(destination.Index(index))
+		fieldSegment := fmt.Sprintf("[%d]", index)
+		childErrors := childTree.write(destination.Index(index), state.child(fieldSegment))
 		if childErrors.Present() {
-			errors.mergeChildErrors(strconv.Itoa(index), childErrors)
+			errors.mergeChildErrors(strconv.Itoa(index), fieldSegment, childErrors)
+		}
+	}
+	return errors
+}
+
+// Writes into a fixed-size array. Indices out of its bounds are reported as errors, since the values would be lost.
+func (paramTree Node) writeIntoArray(destination reflect.Value, state writeState) WriteErrors {
+	var errors WriteErrors
+	for stringIndex, childTree := range paramTree.Children {
+		index, err := strconv.Atoi(stringIndex)
+		if err != nil || index < 0 {
+			errors.append(WriteError{Path: stringIndex, Kind: ErrBadIndex, msg: "not a numeric index"})
+			continue
+		}
+		fieldSegment := fmt.Sprintf("[%d]", index)
+		if index >= destination.Len() {
+			errors.append(WriteError{
+				Path:      stringIndex,
+				FieldPath: fieldSegment,
+				Kind:      ErrOutOfRange,
+				msg:       fmt.Sprintf("index out of range of array of length %d", destination.Len()),
+			})
+			continue
+		}
+		childErrors := childTree.write(destination.Index(index), state.child(fieldSegment))
+		if childErrors.Present() {
+			errors.mergeChildErrors(strconv.Itoa(index), fieldSegment, childErrors)
 		}
 	}
 	return errors
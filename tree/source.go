@@ -0,0 +1,6 @@
+package tree
+
+import "github.com/railsware/go-global/v2"
+
+// Source produces a parameter tree, e.g. by reading it from one of the backends.
+type Source func() (*Node, global.Error)
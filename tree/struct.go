@@ -2,37 +2,73 @@ package tree
 
 import (
 	"reflect"
+	"strings"
+
+	"github.com/railsware/go-global/v2/utils"
 )
 
-func (paramTree Node) writeIntoStruct(destination reflect.Value) WriteErrors {
+func (paramTree Node) writeIntoStruct(destination reflect.Value, state writeState) WriteErrors {
 	var errors WriteErrors
+	suppliedFields := make(map[string]bool, len(paramTree.Children))
 	for fieldName, childTree := range paramTree.Children {
-		structField := lookupFieldByName(destination, fieldName)
-		if !structField.IsValid() {
-			errors.append(writeError{"unknown field", fieldName, true})
+		structField, ok := lookupFieldByName(destination.Type(), fieldName)
+		if !ok {
+			errors.append(WriteError{
+				Path: fieldName, Origin: childTree.Origin, Kind: ErrUnknownField, msg: "unknown field",
+			})
+			continue
+		}
+		suppliedFields[fieldKey(structField)] = true
+		fieldTag, err := utils.ParseFieldTag(structField)
+		if err != nil {
+			errors.append(WriteError{
+				Path:      fieldName,
+				FieldPath: structField.Name,
+				Kind:      ErrInvalidTag,
+				Err:       err,
+				msg:       err.Error(),
+			})
 			continue
 		}
-		errors.mergeChildErrors(fieldName, childTree.Write(structField))
+		childState := state.child(structField.Name).withFieldTag(fieldTag)
+		childErrors := childTree.write(destination.FieldByIndex(structField.Index), childState)
+		errors.mergeChildErrors(fieldName, structField.Name, childErrors)
 	}
+	_, missingErrors := writeMissingFields(destination, suppliedFields, state, map[reflect.Type]bool{})
+	errors.merge(missingErrors)
 	return errors
 }
 
-func lookupFieldByName(structure reflect.Value, name string) reflect.Value {
-	fieldByName := structure.FieldByName(name)
-	if fieldByName.IsValid() {
-		return fieldByName
+// Looks up the field by name, `global:` tag or `json:` tag.
+// Exact matches are preferred, otherwise the match is case-insensitive, like in encoding/json.
+func lookupFieldByName(structure reflect.Type, name string) (reflect.StructField, bool) {
+	fieldByName, ok := structure.FieldByName(name)
+	if ok {
+		return fieldByName, true
 	}
 
 	// This might be inefficient, but fine for one-time loading of a not-crazy-big config
 	for fieldIndex := 0; fieldIndex < structure.NumField(); fieldIndex++ {
-		fieldTag := structure.Type().Field(fieldIndex).Tag
-		if fieldTag.Get("global") == name {
-			return structure.Field(fieldIndex)
+		field := structure.Field(fieldIndex)
+		if utils.TagName(field, "global") == name {
+			return field, true
+		}
+		if utils.TagName(field, "json") == name {
+			return field, true
+		}
+	}
+
+	for fieldIndex := 0; fieldIndex < structure.NumField(); fieldIndex++ {
+		field := structure.Field(fieldIndex)
+		if !field.IsExported() {
+			continue
 		}
-		if fieldTag.Get("json") == name {
-			return structure.Field(fieldIndex)
+		if strings.EqualFold(field.Name, name) ||
+			strings.EqualFold(utils.TagName(field, "global"), name) ||
+			strings.EqualFold(utils.TagName(field, "json"), name) {
+			return field, true
 		}
 	}
 
-	return reflect.Value{}
+	return reflect.StructField{}, false
 }
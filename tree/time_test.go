@@ -0,0 +1,103 @@
+package tree
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTimeStruct struct {
+	Timeout         time.Duration            `json:"timeout"`
+	TTL             time.Duration            `json:"ttl" unit:"s"`
+	Retries         []time.Duration          `json:"retries" unit:"ms"`
+	TimeoutPointer  *time.Duration           `json:"timeout_pointer"`
+	CreatedAt       time.Time                `json:"created_at"`
+	Date            time.Time                `json:"date" layout:"2006-01-02"`
+	Dates           map[string]time.Time     `json:"dates" layout:"2006-01-02"`
+	DurationsByName map[string]time.Duration `json:"durations_by_name"`
+}
+
+func TestWriteTimeValues(t *testing.T) {
+	t.Parallel()
+
+	tree := &Node{
+		Children: map[string]*Node{
+			"timeout":         {Value: "1m30s"},
+			"ttl":             {Value: "60"},
+			"retries":         {Children: map[string]*Node{"0": {Value: "100"}, "1": {Value: "2s"}}},
+			"timeout_pointer": {Value: "5s"},
+			"created_at":      {Value: "2023-04-05T06:07:08Z"},
+			"date":            {Value: "2023-04-05"},
+			"dates":           {Children: map[string]*Node{"launch": {Value: "2024-01-02"}}},
+		},
+	}
+
+	var destination testTimeStruct
+	errors := tree.Write(reflect.ValueOf(&destination))
+	require.False(t, errors.Present(), errors.Error())
+
+	timeout := 5 * time.Second
+
+	assert.Equal(t, 90*time.Second, destination.Timeout)
+	assert.Equal(t, time.Minute, destination.TTL)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 2 * time.Second}, destination.Retries)
+	assert.Equal(t, &timeout, destination.TimeoutPointer)
+	assert.Equal(t, time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC), destination.CreatedAt)
+	assert.Equal(t, time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC), destination.Date)
+	assert.Equal(t, map[string]time.Time{"launch": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}, destination.Dates)
+}
+
+func TestWriteTimeValuesErrors(t *testing.T) {
+	t.Parallel()
+
+	tree := &Node{
+		Children: map[string]*Node{
+			"timeout":           {Value: "30"},
+			"date":              {Value: "05/04/2023"},
+			"durations_by_name": {Children: map[string]*Node{"foo": {Value: "soon"}}},
+		},
+	}
+
+	var destination testTimeStruct
+	errors := tree.Write(reflect.ValueOf(&destination))
+
+	expected := []expectedError{
+		{
+			"timeout", "Timeout", "30", ErrParse,
+			`cannot read time.Duration param value: time: missing unit in duration "30"`,
+		},
+		{
+			"date", "Date", "05/04/2023", ErrParse,
+			`cannot read time.Time param value (layout "2006-01-02"): ` +
+				`parsing time "05/04/2023" as "2006-01-02": cannot parse "05/04/2023" as "2006"`,
+		},
+		{
+			"durations_by_name/foo", `DurationsByName["foo"]`, "soon", ErrParse,
+			`cannot read time.Duration param value: time: invalid duration "soon"`,
+		},
+	}
+	assert.ElementsMatch(t, expected, expectedErrors(errors))
+}
+
+func TestWriteWithInvalidTags(t *testing.T) {
+	t.Parallel()
+
+	type invalidTags struct {
+		BadUnit   time.Duration `json:"bad_unit" unit:"parsecs"`
+		BadOption string        `global:"bad_option,requird"`
+	}
+
+	tree := &Node{Children: map[string]*Node{"bad_unit": {Value: "1"}}}
+
+	var destination invalidTags
+	errors := tree.Write(reflect.ValueOf(&destination))
+
+	expected := []expectedError{
+		{"bad_unit", "BadUnit", "", ErrInvalidTag, `invalid unit "parsecs"`},
+		{"bad_option", "BadOption", "", ErrInvalidTag, `unknown option "requird" in global tag`},
+	}
+	assert.ElementsMatch(t, expected, expectedErrors(errors))
+}
@@ -0,0 +1,110 @@
+package tree
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/railsware/go-global/v2/utils"
+)
+
+// Validator is implemented by config structs that check themselves after loading.
+type Validator interface {
+	Validate() error
+}
+
+var validatorType = reflect.TypeOf((*Validator)(nil)).Elem()
+
+// Validate checks the rules of `validate:` tags and calls Validate methods of destination and nested structs.
+// Nested structs are validated before the structs containing them.
+// Failures are reported as ErrValidation, with the same paths as write errors.
+func Validate(destination reflect.Value) WriteErrors {
+	return validateValue(destination, true)
+}
+
+func validateValue(value reflect.Value, callValidator bool) WriteErrors {
+	var errors WriteErrors
+
+	switch value.Kind() { //nolint:exhaustive // other kinds have nothing nested
+	case reflect.Ptr, reflect.Interface:
+		if !value.IsNil() {
+			errors.merge(validateValue(value.Elem(), callValidator))
+		}
+	case reflect.Struct:
+		if isDecodable(value.Type()) {
+			break
+		}
+		errors.merge(validateStruct(value))
+		if callValidator {
+			errors.merge(callValidate(value))
+		}
+	case reflect.Slice, reflect.Array:
+		for index := 0; index < value.Len(); index++ {
+			errors.mergeChildErrors(strconv.Itoa(index), fmt.Sprintf("[%d]", index), validateValue(value.Index(index), true))
+		}
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			break
+		}
+		iterator := value.MapRange()
+		for iterator.Next() {
+			key := iterator.Key().String()
+			errors.mergeChildErrors(key, fmt.Sprintf("[%q]", key), validateValue(iterator.Value(), true))
+		}
+	}
+
+	return errors
+}
+
+func validateStruct(value reflect.Value) WriteErrors {
+	var errors WriteErrors
+
+	for fieldIndex := 0; fieldIndex < value.NumField(); fieldIndex++ {
+		structField := value.Type().Field(fieldIndex)
+		if !structField.IsExported() {
+			continue
+		}
+		field := value.Field(fieldIndex)
+
+		var fieldErrors WriteErrors
+		if rules, ok := structField.Tag.Lookup("validate"); ok {
+			fieldErrors.merge(checkRules(field, rules))
+		}
+
+		if structField.Anonymous {
+			// fields of embedded structs are promoted, and so is the Validate method
+			fieldErrors.merge(validateValue(field, false))
+			errors.merge(fieldErrors)
+			continue
+		}
+
+		fieldErrors.merge(validateValue(field, true))
+		errors.mergeChildErrors(utils.ParamName(structField), structField.Name, fieldErrors)
+	}
+
+	return errors
+}
+
+// Calls the Validate method, which might have a pointer receiver.
+func callValidate(value reflect.Value) WriteErrors {
+	if !value.CanAddr() {
+		addressableValue := reflect.New(value.Type()).Elem()
+		addressableValue.Set(value)
+		value = addressableValue
+	}
+
+	var validator Validator
+	switch {
+	case value.Type().Implements(validatorType):
+		validator = value.Interface().(Validator) //nolint:forcetypeassert
+	case value.Addr().Type().Implements(validatorType):
+		validator = value.Addr().Interface().(Validator) //nolint:forcetypeassert
+	default:
+		return WriteErrors{}
+	}
+
+	if err := validator.Validate(); err != nil {
+		return newWriteErrors(WriteError{Kind: ErrValidation, Err: err, msg: err.Error()})
+	}
+	return WriteErrors{}
+}
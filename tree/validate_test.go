@@ -0,0 +1,132 @@
+package tree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testValidatedReplica struct {
+	Host string `json:"host" validate:"hostport"`
+}
+
+type testValidatedDatabase struct {
+	PoolSize int                    `json:"pool_size" validate:"min=1,max=100"`
+	Timeout  time.Duration          `json:"timeout" validate:"min=1s"`
+	Mode     string                 `json:"mode" validate:"oneof=primary replica"`
+	URL      string                 `json:"url" validate:"url"`
+	Name     string                 `json:"name" validate:"regexp=^[a-z]{2,5}$"`
+	Code     string                 `json:"code" validate:"len=3"`
+	Tags     []string               `json:"tags" validate:"max=2"`
+	Replicas []testValidatedReplica `json:"replicas"`
+	Optional *int                   `json:"optional" validate:"min=1"`
+}
+
+type testValidatedConfig struct {
+	Database testValidatedDatabase `json:"database"`
+	Mirrors  map[string]testValidatedDatabase
+	Broken   string `json:"broken" validate:"min=abc,between=1"`
+}
+
+var validationCalls []string
+
+func (database testValidatedDatabase) Validate() error {
+	validationCalls = append(validationCalls, "database:"+database.Mode)
+	if database.Mode == "replica" && len(database.Replicas) > 0 {
+		return errors.New("replica cannot have replicas")
+	}
+	return nil
+}
+
+func (config *testValidatedConfig) Validate() error {
+	validationCalls = append(validationCalls, "config")
+	return nil
+}
+
+func TestValidate(t *testing.T) { //nolint:paralleltest // uses validationCalls
+	valid := testValidatedDatabase{
+		PoolSize: 10,
+		Timeout:  time.Second,
+		Mode:     "primary",
+		URL:      "postgres://localhost/db",
+		Name:     "main",
+		Code:     "abc",
+		Tags:     []string{"a"},
+		Replicas: []testValidatedReplica{{Host: "localhost:5432"}},
+	}
+	invalid := testValidatedDatabase{
+		PoolSize: 0,
+		Timeout:  time.Millisecond,
+		Mode:     "replica",
+		URL:      "localhost/db",
+		Name:     "Main",
+		Code:     "abcd",
+		Tags:     []string{"a", "b", "c"},
+		Replicas: []testValidatedReplica{{Host: "localhost"}},
+	}
+
+	config := testValidatedConfig{
+		Database: valid,
+		Mirrors:  map[string]testValidatedDatabase{"east": invalid},
+	}
+
+	validationCalls = nil
+	errors := Validate(reflect.ValueOf(&config))
+
+	expected := []expectedError{
+		{"broken", "Broken", "", ErrInvalidTag, `validate rule "min": strconv.Atoi: parsing "abc": invalid syntax`},
+		{"broken", "Broken", "", ErrInvalidTag, `validate rule "between": unknown rule`},
+		{"Mirrors/east/pool_size", `Mirrors["east"].PoolSize`, "0", ErrValidation, "validation failed: must be at least 1"},
+		{"Mirrors/east/timeout", `Mirrors["east"].Timeout`, "1ms", ErrValidation, "validation failed: must be at least 1s"},
+		{
+			"Mirrors/east/url", `Mirrors["east"].URL`, "localhost/db", ErrValidation,
+			"validation failed: must be an absolute URL",
+		},
+		{"Mirrors/east/name", `Mirrors["east"].Name`, "Main", ErrValidation, "validation failed: must match ^[a-z]{2,5}$"},
+		{"Mirrors/east/code", `Mirrors["east"].Code`, "abcd", ErrValidation, "validation failed: length must be 3"},
+		{
+			"Mirrors/east/tags", `Mirrors["east"].Tags`, "[a b c]", ErrValidation,
+			"validation failed: length must be at most 2",
+		},
+		{
+			"Mirrors/east/replicas/0/host", `Mirrors["east"].Replicas[0].Host`, "localhost", ErrValidation,
+			"validation failed: must be host:port",
+		},
+		{"Mirrors/east", `Mirrors["east"]`, "", ErrValidation, "replica cannot have replicas"},
+	}
+	assert.ElementsMatch(t, expected, expectedErrors(errors))
+	assert.Equal(t, []string{"database:primary", "database:replica", "config"}, validationCalls, "bottom-up")
+}
+
+func TestWriteConfigValidates(t *testing.T) { //nolint:paralleltest // uses validationCalls
+	type config struct {
+		Database testValidatedDatabase `json:"database"`
+	}
+
+	tree := &Node{
+		Children: map[string]*Node{
+			"database": {
+				Children: map[string]*Node{
+					"pool_size": {Value: "1000"},
+					"timeout":   {Value: "5s"},
+					"mode":      {Value: "primary"},
+					"url":       {Value: "https://example.com"},
+					"name":      {Value: "db"},
+					"code":      {Value: "abc"},
+				},
+			},
+		},
+	}
+
+	var loadedConfig config
+	err := tree.WriteConfig(&loadedConfig, WriteOptions{})
+	assert.Equal(t, "global: database/pool_size: validation failed: must be at most 100", err.Error())
+	assert.ErrorIs(t, err, ErrValidation)
+
+	tree.Children["database"].Children["pool_size"].Value = "not a number"
+	err = tree.WriteConfig(&loadedConfig, WriteOptions{})
+	assert.NotErrorIs(t, err, ErrValidation, "not validated after write errors")
+}
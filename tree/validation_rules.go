@@ -0,0 +1,237 @@
+package tree
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var errRuleNotApplicable = errors.New("rule is not applicable to the type")
+
+type validationRule struct {
+	name  string
+	param string
+}
+
+// Parses rules like "min=1,max=10". A regexp rule takes the rest of the tag, so it can contain commas.
+func parseValidationRules(tag string) []validationRule {
+	var rules []validationRule
+	for tag != "" {
+		var rule string
+		if strings.HasPrefix(tag, "regexp=") {
+			rule, tag = tag, ""
+		} else {
+			rule, tag, _ = strings.Cut(tag, ",")
+		}
+		name, param, _ := strings.Cut(rule, "=")
+		rules = append(rules, validationRule{strings.TrimSpace(name), param})
+	}
+	return rules
+}
+
+// Checks the rules of a `validate:` tag against the value. Nil pointers are not checked.
+func checkRules(value reflect.Value, tag string) WriteErrors {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return WriteErrors{}
+		}
+		value = value.Elem()
+	}
+
+	var writeErrors WriteErrors
+	for _, rule := range parseValidationRules(tag) {
+		failure, err := checkRule(value, rule)
+		switch {
+		case err != nil:
+			err = fmt.Errorf("validate rule %q: %w", rule.name, err)
+			writeErrors.append(WriteError{Kind: ErrInvalidTag, Err: err, msg: err.Error()})
+		case failure != "":
+			writeErrors.append(WriteError{
+				Value: fmt.Sprint(value.Interface()),
+				Kind:  ErrValidation,
+				msg:   fmt.Sprintf("validation failed: %s", failure),
+			})
+		}
+	}
+	return writeErrors
+}
+
+// Returns a description of the failure, or an error if the rule itself is invalid.
+func checkRule(value reflect.Value, rule validationRule) (string, error) {
+	switch rule.name {
+	case "min":
+		return checkBound(value, rule.param, -1)
+	case "max":
+		return checkBound(value, rule.param, 1)
+	case "len":
+		return checkLen(value, rule.param)
+	case "oneof":
+		return checkOneOf(value, rule.param)
+	case "regexp":
+		return checkRegexp(value, rule.param)
+	case "url":
+		return checkURL(value)
+	case "hostport":
+		return checkHostPort(value)
+	default:
+		return "", errors.New("unknown rule")
+	}
+}
+
+// Compares numbers, durations or lengths to the bound, failing if the result equals failingSign.
+func checkBound(value reflect.Value, param string, failingSign int) (string, error) {
+	var (
+		comparison int
+		what       = "must be"
+	)
+
+	switch value.Kind() { //nolint:exhaustive // other kinds are not applicable
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var bound int64
+		var err error
+		if value.Type() == durationType {
+			var duration time.Duration
+			duration, err = time.ParseDuration(param)
+			bound = int64(duration)
+		} else {
+			bound, err = strconv.ParseInt(param, 10, 64)
+		}
+		if err != nil {
+			return "", err
+		}
+		comparison = compare(value.Int(), bound)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		bound, err := strconv.ParseUint(param, 10, 64)
+		if err != nil {
+			return "", err
+		}
+		comparison = compare(value.Uint(), bound)
+	case reflect.Float32, reflect.Float64:
+		bound, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return "", err
+		}
+		comparison = compare(value.Float(), bound)
+	default:
+		length, ok := lengthOf(value)
+		if !ok {
+			return "", errRuleNotApplicable
+		}
+		bound, err := strconv.Atoi(param)
+		if err != nil {
+			return "", err
+		}
+		comparison = compare(length, bound)
+		what = "length must be"
+	}
+
+	if comparison != failingSign {
+		return "", nil
+	}
+	if failingSign < 0 {
+		return fmt.Sprintf("%s at least %s", what, param), nil
+	}
+	return fmt.Sprintf("%s at most %s", what, param), nil
+}
+
+func checkLen(value reflect.Value, param string) (string, error) {
+	length, ok := lengthOf(value)
+	if !ok {
+		return "", errRuleNotApplicable
+	}
+	expectedLength, err := strconv.Atoi(param)
+	if err != nil {
+		return "", err
+	}
+	if length != expectedLength {
+		return fmt.Sprintf("length must be %d", expectedLength), nil
+	}
+	return "", nil
+}
+
+func checkOneOf(value reflect.Value, param string) (string, error) {
+	switch value.Kind() { //nolint:exhaustive // other kinds are not applicable
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return "", errRuleNotApplicable
+	}
+
+	options := strings.Fields(param)
+	formattedValue := fmt.Sprint(value.Interface())
+	for _, option := range options {
+		if option == formattedValue {
+			return "", nil
+		}
+	}
+	return fmt.Sprintf("must be one of %s", strings.Join(options, ", ")), nil
+}
+
+func checkRegexp(value reflect.Value, param string) (string, error) {
+	if value.Kind() != reflect.String {
+		return "", errRuleNotApplicable
+	}
+	pattern, err := regexp.Compile(param)
+	if err != nil {
+		return "", err
+	}
+	if !pattern.MatchString(value.String()) {
+		return fmt.Sprintf("must match %s", param), nil
+	}
+	return "", nil
+}
+
+func checkURL(value reflect.Value) (string, error) {
+	if value.Kind() != reflect.String {
+		return "", errRuleNotApplicable
+	}
+	parsedURL, err := url.Parse(value.String())
+	if err != nil || parsedURL.Scheme == "" || parsedURL.Host == "" {
+		return "must be an absolute URL", nil
+	}
+	return "", nil
+}
+
+func checkHostPort(value reflect.Value) (string, error) {
+	if value.Kind() != reflect.String {
+		return "", errRuleNotApplicable
+	}
+	host, port, err := net.SplitHostPort(value.String())
+	if err != nil || host == "" {
+		return "must be host:port", nil
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "must be host:port with a numeric port", nil
+	}
+	return "", nil
+}
+
+func lengthOf(value reflect.Value) (int, bool) {
+	switch value.Kind() { //nolint:exhaustive // other kinds have no length
+	case reflect.String:
+		return utf8.RuneCountInString(value.String()), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return value.Len(), true
+	default:
+		return 0, false
+	}
+}
+
+func compare[T int | int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,39 @@
+package tree
+
+import (
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/utils"
+)
+
+// WriteConfig writes the tree into config, validates it and joins the errors.
+//   - config must be a pointer to a struct.
+//   - Validation only runs if there are no errors other than warnings, see Validate.
+//   - If options.IgnoreUnmappedParams is set, warnings about params with no matching config field are dropped.
+//   - If options.Provenance is set, it collects the origins of the written values.
+func (paramTree Node) WriteConfig(config interface{}, options WriteOptions) global.Error {
+	reflectedConfig, err := utils.ReflectConfig(config)
+	if err != nil {
+		return err
+	}
+
+	if paramTree.Children == nil {
+		// an empty tree is still a tree, not a leaf
+		paramTree.Children = map[string]*Node{}
+	}
+
+	errors := paramTree.WriteWithOptions(reflectedConfig, options)
+	if errors.Warning() {
+		errors.merge(Validate(reflectedConfig))
+	}
+	if !errors.Present() {
+		return nil
+	}
+
+	joinedError := errors.Join()
+
+	if joinedError.Warning() && options.IgnoreUnmappedParams {
+		return nil
+	}
+
+	return joinedError
+}
@@ -1,31 +1,84 @@
 package tree
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/railsware/go-global/v2"
 )
 
-type writeError struct {
-	msg         string
-	path        string
-	isPathError bool
+// Categories of write errors, to be matched with errors.Is.
+var (
+	ErrUnknownField    = errors.New("unknown field")
+	ErrParse           = errors.New("cannot parse value")
+	ErrUnsupportedType = errors.New("unsupported type")
+	ErrBadIndex        = errors.New("bad index")
+	ErrOutOfRange      = errors.New("index out of range")
+	ErrIgnoredValue    = errors.New("ignored value")
+	ErrNotWritable     = errors.New("not writable")
+	ErrInvalidTag      = errors.New("invalid tag")
+	ErrMissing         = errors.New("missing parameter")
+	ErrValidation      = errors.New("validation failed")
+)
+
+// WriteError describes a single problem with writing a parameter into the config.
+type WriteError struct {
+	// Path of the parameter relative to the root of the tree, separated with slashes.
+	Path string
+	// FieldPath is the path of the Go value, e.g. Database.URLs[0].
+	FieldPath string
+	// Value is the raw parameter value.
+	Value string
+	// Origin of the parameter, if known.
+	Origin *global.Origin
+	// Kind is one of the Err* categories.
+	Kind error
+	// Err is the underlying cause, if any.
+	Err error
+
+	msg string
+}
+
+func (err *WriteError) Error() string {
+	msg := err.msg
+	if err.Origin != nil && err.Origin.Name != "" {
+		msg = fmt.Sprintf("%s (from %s)", msg, err.Origin.Name)
+	}
+	if err.Path == "" {
+		return msg
+	}
+	return fmt.Sprintf("%s: %s", err.Path, msg)
 }
 
+// Unwrap returns the category and the underlying cause.
+func (err *WriteError) Unwrap() []error {
+	if err.Err == nil {
+		return []error{err.Kind}
+	}
+	return []error{err.Kind, err.Err}
+}
+
+// Warning reports whether the parameter could not be mapped to the config, as opposed to a failure to write it.
+func (err *WriteError) Warning() bool {
+	return err.Kind == ErrUnknownField || err.Kind == ErrBadIndex || err.Kind == ErrIgnoredValue
+}
+
+// WriteErrors collects all problems found while writing a tree.
+// It implements global.Error, and errors.As can extract each *WriteError from it.
 type WriteErrors struct {
-	errors []writeError
+	errors []WriteError
 }
 
-func newWriteErrors(msg string) WriteErrors {
-	return WriteErrors{[]writeError{{msg: msg, isPathError: false}}}
+func newWriteErrors(err WriteError) WriteErrors {
+	return WriteErrors{[]WriteError{err}}
 }
 
 func (we *WriteErrors) Present() bool {
 	return len(we.errors) > 0
 }
 
-func (we *WriteErrors) append(err writeError) {
+func (we *WriteErrors) append(err WriteError) {
 	we.errors = append(we.errors, err)
 }
 
@@ -33,33 +86,76 @@ func (we *WriteErrors) merge(newErrors WriteErrors) {
 	we.errors = append(we.errors, newErrors.errors...)
 }
 
-func (we *WriteErrors) mergeChildErrors(childName string, childErrors WriteErrors) {
+// Attributes all errors to the given origin.
+func (we *WriteErrors) setOrigin(origin *global.Origin) {
+	for index := range we.errors {
+		we.errors[index].Origin = origin
+	}
+}
+
+// Merges errors of a child, prepending the parameter name and the Go field path segment to their paths.
+// fieldSegment is either a field name or an index in brackets.
+func (we *WriteErrors) mergeChildErrors(childName string, fieldSegment string, childErrors WriteErrors) {
 	for _, childErr := range childErrors.errors {
-		if childErr.path == "" {
-			childErr.path = childName
+		if childErr.Path == "" {
+			childErr.Path = childName
 		} else {
-			childErr.path = fmt.Sprintf("%s/%s", childName, childErr.path)
+			childErr.Path = fmt.Sprintf("%s/%s", childName, childErr.Path)
 		}
+		childErr.FieldPath = joinFieldPath(fieldSegment, childErr.FieldPath)
 		we.errors = append(we.errors, childErr)
 	}
 }
 
-func (we *WriteErrors) Join() global.Error {
+// Joins Go field paths, e.g. "Database" and "URLs[0]" into "Database.URLs[0]".
+func joinFieldPath(parent string, child string) string {
+	switch {
+	case parent == "":
+		return child
+	case child == "":
+		return parent
+	case strings.HasPrefix(child, "["):
+		return parent + child
+	default:
+		return fmt.Sprintf("%s.%s", parent, child)
+	}
+}
+
+// Errors returns all collected errors.
+func (we WriteErrors) Errors() []*WriteError {
+	errs := make([]*WriteError, 0, len(we.errors))
+	for index := range we.errors {
+		errs = append(errs, &we.errors[index])
+	}
+	return errs
+}
+
+func (we WriteErrors) Error() string {
 	msgs := make([]string, 0, len(we.errors))
-	isWarning := true
-	for _, err := range we.errors {
-		msg := fmt.Sprintf("%s: %s", err.path, err.msg)
-		msgs = append(msgs, msg)
-		if !err.isPathError {
-			isWarning = false
-		}
+	for index := range we.errors {
+		msgs = append(msgs, we.errors[index].Error())
 	}
+	return fmt.Sprintf("global: %s", strings.Join(msgs, ", "))
+}
 
-	msg := fmt.Sprintf("global: %s", strings.Join(msgs, ", "))
+// Warning reports whether all collected errors are warnings.
+func (we WriteErrors) Warning() bool {
+	for index := range we.errors {
+		if !we.errors[index].Warning() {
+			return false
+		}
+	}
+	return true
+}
 
-	if isWarning {
-		return global.NewWarning(msg)
+func (we WriteErrors) Unwrap() []error {
+	errs := make([]error, 0, len(we.errors))
+	for _, err := range we.Errors() {
+		errs = append(errs, err)
 	}
+	return errs
+}
 
-	return global.NewError(msg)
+func (we *WriteErrors) Join() global.Error {
+	return WriteErrors{append([]WriteError(nil), we.errors...)}
 }
@@ -0,0 +1,71 @@
+package tree
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/utils"
+)
+
+type WriteOptions struct {
+	// If RequireAll is set, every field is treated as tagged `global:",required"`.
+	RequireAll bool
+	// ParamPrefix is prepended to param paths in missing parameter errors,
+	// so that they show the full name of the expected param.
+	ParamPrefix string
+	// ParamName, if set, names the param of a path in missing parameter errors instead of ParamPrefix,
+	// e.g. when params of different paths are loaded from different prefixes.
+	ParamName func(path string) string
+	// If IgnoreUnmappedParams is set, WriteConfig drops warnings about params with no matching config field.
+	IgnoreUnmappedParams bool
+	// Provenance, if not nil, collects the origins of the written values by Go field path, even if writing fails.
+	// Origins recorded before, e.g. by another loader, are kept unless their fields are written again.
+	Provenance global.Provenance
+}
+
+// writeState is passed down the tree while writing.
+type writeState struct {
+	options *WriteOptions
+	// fieldTag holds the options of the closest struct field
+	fieldTag utils.FieldTag
+	// fieldPath is the Go field path of the destination, e.g. "Database.URLs[0]"
+	fieldPath string
+}
+
+func (state writeState) withFieldTag(fieldTag utils.FieldTag) writeState {
+	state.fieldTag = fieldTag
+	return state
+}
+
+// Returns the state of a child at the Go field path segment: a field name or an index in brackets.
+func (state writeState) child(fieldSegment string) writeState {
+	state.fieldPath = joinFieldPath(state.fieldPath, fieldSegment)
+	return state
+}
+
+// Records the origin of the value written at the current field path.
+func (state writeState) recordOrigin(origin *global.Origin) {
+	if state.options.Provenance != nil && origin != nil {
+		state.options.Provenance[state.fieldPath] = *origin
+	}
+}
+
+// WriteWithOptions writes the tree into destination.
+func (paramTree Node) WriteWithOptions(destination reflect.Value, options WriteOptions) WriteErrors {
+	errors := paramTree.write(destination, writeState{options: &options})
+	for index := range errors.errors {
+		if err := &errors.errors[index]; err.Kind == ErrMissing {
+			err.msg = fmt.Sprintf("missing parameter %s", options.paramName(err.Path))
+		}
+	}
+	return errors
+}
+
+// Returns the full name of the param of the path, for missing parameter errors.
+func (options WriteOptions) paramName(path string) string {
+	if options.ParamName != nil {
+		return options.ParamName(path)
+	}
+	return options.ParamPrefix + path
+}
@@ -320,3 +320,24 @@ func TestWrite(t *testing.T) { //nolint:maintidx
 
 	assert.Equal(t, expectedMergedStruct, testStruct, "merging changes works correctly")
 }
+
+func TestWriteMatchesFieldsCaseInsensitively(t *testing.T) {
+	t.Parallel()
+
+	var testStruct testStructType
+
+	tree := &Node{
+		Children: map[string]*Node{
+			"str":    {Value: "foo"},
+			"INT":    {Value: "123"},
+			"Strmap": {Children: map[string]*Node{"Key": {Value: "value"}}},
+		},
+	}
+
+	errors := tree.Write(reflect.ValueOf(&testStruct))
+	require.False(t, errors.Present())
+
+	assert.Equal(t, "foo", testStruct.Str)
+	assert.Equal(t, 123, testStruct.Int)
+	assert.Equal(t, map[string]string{"Key": "value"}, testStruct.StrMap)
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// FieldTag holds the options of a config struct field, read from its tags.
+type FieldTag struct {
+	// Unit of bare integers written into time.Duration values, from the `unit:` tag, e.g. `unit:"s"`.
+	// If not set, durations must have a unit, e.g. "30s".
+	Unit time.Duration
+	// Layout of time.Time values, from the `layout:` tag. Defaults to RFC 3339.
+	Layout string
+	// Default is the raw value written into the field when no param is supplied, from the `default:` tag.
+	Default    string
+	HasDefault bool
+	// Split is the separator of list values written into slices, from the `split:` tag, e.g. `split:","`.
+	Split string
+	// Label pins the param of the field to a labelled version in Parameter Store, from the `label:` tag,
+	// e.g. `label:"stable"`. A number pins a version, e.g. `label:"3"`.
+	Label string
+	// Required fields must be supplied by a param or a default, from the `global:",required"` tag option.
+	Required bool
+	// Sensitive fields are masked in config dumps, from the `global:",sensitive"` tag option.
+	Sensitive bool
+}
+
+// ParseFieldTag reads the options of the field from its tags.
+func ParseFieldTag(field reflect.StructField) (FieldTag, error) {
+	fieldTag := FieldTag{
+		Layout: field.Tag.Get("layout"),
+		Split:  field.Tag.Get("split"),
+		Label:  field.Tag.Get("label"),
+	}
+
+	if unit := field.Tag.Get("unit"); unit != "" {
+		unitDuration, err := time.ParseDuration("1" + unit)
+		if err != nil {
+			return FieldTag{}, fmt.Errorf("invalid unit %q", unit)
+		}
+		fieldTag.Unit = unitDuration
+	}
+
+	fieldTag.Default, fieldTag.HasDefault = field.Tag.Lookup("default")
+
+	globalTag := strings.Split(field.Tag.Get("global"), ",")
+	for _, option := range globalTag[1:] {
+		switch option {
+		case "required":
+			fieldTag.Required = true
+		case "sensitive":
+			fieldTag.Sensitive = true
+		default:
+			return FieldTag{}, fmt.Errorf("unknown option %q in global tag", option)
+		}
+	}
+
+	return fieldTag, nil
+}
+
+// TagName returns the name part of a tag like `json:"name,omitempty"`.
+func TagName(field reflect.StructField, key string) string {
+	name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+	return name
+}
+
+// ParamName returns the name of the param matching the field: the `global:` tag, the `json:` tag or the field name.
+func ParamName(field reflect.StructField) string {
+	if name := TagName(field, "global"); name != "" {
+		return name
+	}
+	if name := TagName(field, "json"); name != "" && name != "-" {
+		return name
+	}
+	return field.Name
+}
@@ -0,0 +1,175 @@
+package yaml
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/tree"
+	goyaml "gopkg.in/yaml.v3"
+)
+
+const (
+	defaultSection = "default"
+	mergeKey       = "<<"
+	nullTag        = "!!null"
+)
+
+var yamlExtensions = []string{".yml", ".yaml"}
+
+// Builds a tree out of all YAML files in the directory, recursing into subdirectories.
+func buildDirectoryTree(dir string, environment string) (*tree.Node, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	dirTree := &tree.Node{Children: make(map[string]*tree.Node)}
+
+	for _, entry := range entries {
+		entryPath := filepath.Join(dir, entry.Name())
+		var (
+			namespace string
+			entryTree *tree.Node
+		)
+		switch {
+		case entry.IsDir():
+			namespace = entry.Name()
+			entryTree, err = buildDirectoryTree(entryPath, environment)
+		case isYAMLFile(entry.Name()):
+			namespace = strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+			entryTree, err = buildFileTree(entryPath, environment)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if entryTree != nil {
+			dirTree.Children[namespace] = tree.Merge(dirTree.Children[namespace], entryTree)
+		}
+	}
+
+	return dirTree, nil
+}
+
+func isYAMLFile(name string) bool {
+	extension := filepath.Ext(name)
+	for _, yamlExtension := range yamlExtensions {
+		if extension == yamlExtension {
+			return true
+		}
+	}
+	return false
+}
+
+// Builds a tree out of a single file by merging its "default" section with the environment section.
+// Like Ruby Global's deep merge, a list of the environment section replaces the default list.
+func buildFileTree(path string, environment string) (*tree.Node, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var document goyaml.Node
+	if err := goyaml.Unmarshal(contents, &document); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	fileTree, err := buildTree(&document, path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if fileTree == nil || fileTree.Children == nil {
+		return nil, nil
+	}
+
+	return tree.Merge(fileTree.Children[defaultSection], fileTree.Children[environment]), nil
+}
+
+// Converts a YAML node of the file at path into a tree. Mappings and sequences become children, scalars become values.
+// Returns nil for null values.
+func buildTree(node *goyaml.Node, path string) (*tree.Node, error) {
+	switch node.Kind {
+	case goyaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return buildTree(node.Content[0], path)
+	case goyaml.AliasNode:
+		return buildTree(node.Alias, path)
+	case goyaml.ScalarNode:
+		if node.Tag == nullTag {
+			return nil, nil
+		}
+		origin := &global.Origin{Backend: global.BackendYAML, Name: fmt.Sprintf("%s:%d", path, node.Line)}
+		return &tree.Node{Value: node.Value, Origin: origin}, nil
+	case goyaml.SequenceNode:
+		sequenceTree := &tree.Node{Children: make(map[string]*tree.Node, len(node.Content)), Sequence: true}
+		for index, item := range node.Content {
+			itemTree, err := buildTree(item, path)
+			if err != nil {
+				return nil, err
+			}
+			if itemTree != nil {
+				sequenceTree.Children[strconv.Itoa(index)] = itemTree
+			}
+		}
+		return sequenceTree, nil
+	case goyaml.MappingNode:
+		return buildMappingTree(node, path)
+	default:
+		return nil, fmt.Errorf("line %d: unexpected YAML node", node.Line)
+	}
+}
+
+func buildMappingTree(node *goyaml.Node, path string) (*tree.Node, error) {
+	mappingTree := &tree.Node{Children: make(map[string]*tree.Node, len(node.Content)/2)}
+	var mergeSources []*goyaml.Node
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Kind != goyaml.ScalarNode {
+			return nil, fmt.Errorf("line %d: mapping keys must be scalars", key.Line)
+		}
+
+		if key.Value == mergeKey {
+			// `<<: *anchor` or `<<: [*first, *second]`
+			if value.Kind == goyaml.SequenceNode {
+				mergeSources = append(mergeSources, value.Content...)
+			} else {
+				mergeSources = append(mergeSources, value)
+			}
+			continue
+		}
+
+		valueTree, err := buildTree(value, path)
+		if err != nil {
+			return nil, err
+		}
+		if valueTree != nil {
+			mappingTree.Children[key.Value] = valueTree
+		}
+	}
+
+	// As in YAML, merging is shallow: own keys win, then earlier merge sources win over later ones.
+	for _, source := range mergeSources {
+		sourceTree, err := buildTree(source, path)
+		if err != nil {
+			return nil, err
+		}
+		if sourceTree == nil {
+			continue
+		}
+		for key, child := range sourceTree.Children {
+			if _, ok := mappingTree.Children[key]; !ok {
+				mappingTree.Children[key] = child
+			}
+		}
+	}
+
+	return mappingTree, nil
+}
@@ -0,0 +1,134 @@
+package yaml
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/tree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDirectoryTree(t *testing.T) {
+	t.Parallel()
+
+	expectedTree := &tree.Node{
+		Children: map[string]*tree.Node{
+			"database": {
+				Children: map[string]*tree.Node{
+					"pool_size": {Value: "5"},
+					"urls": {
+						Children: map[string]*tree.Node{
+							"0": {Value: "postgres://primary/prod"},
+							"1": {Value: "postgres://replica/prod"},
+						},
+					},
+					"options": {
+						Children: map[string]*tree.Node{
+							"sslmode": {Value: "require"},
+							"timeout": {Value: "10"},
+						},
+					},
+				},
+			},
+			"features": {
+				Children: map[string]*tree.Node{
+					"signup": {Value: "true"},
+					"beta":   {Value: "false"},
+				},
+			},
+			"nested": {
+				Children: map[string]*tree.Node{
+					"mail": {
+						Children: map[string]*tree.Node{
+							"host": {Value: "smtp.example.com"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	paramTree, err := buildDirectoryTree("testdata/config", "production")
+	require.NoError(t, err)
+
+	assert.True(t, expectedTree.Equal(paramTree), "unexpected tree %#v", paramTree)
+	assert.Equal(
+		t,
+		&global.Origin{Backend: global.BackendYAML, Name: "testdata/config/database.yml:2"},
+		paramTree.Children["database"].Children["pool_size"].Origin,
+	)
+}
+
+func TestBuildDirectoryTreeWithMergeKey(t *testing.T) {
+	t.Parallel()
+
+	paramTree, err := buildDirectoryTree("testdata/config", "development")
+	require.NoError(t, err)
+
+	assert.Equal(t, "5", paramTree.Children["database"].Children["pool_size"].Value)
+	assert.Equal(t, "postgres://localhost/default", paramTree.Children["database"].Children["urls"].Children["0"].Value)
+}
+
+func TestLoadConfigFromDirectory(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Database struct {
+			PoolSize int               `json:"pool_size"`
+			URLs     []string          `json:"urls"`
+			Options  map[string]string `json:"options"`
+		} `json:"database"`
+		Features struct {
+			Signup bool `json:"signup"`
+			Beta   bool `json:"beta"`
+		} `json:"features"`
+		Nested struct {
+			Mail struct {
+				Host string `json:"host"`
+			} `json:"mail"`
+		} `json:"nested"`
+	}
+
+	var loadedConfig config
+	err := LoadConfigFromDirectory("testdata/config", "production", &loadedConfig)
+	require.Nil(t, err)
+
+	assert.Equal(t, 5, loadedConfig.Database.PoolSize)
+	assert.Equal(t, []string{"postgres://primary/prod", "postgres://replica/prod"}, loadedConfig.Database.URLs)
+	assert.Equal(t, map[string]string{"sslmode": "require", "timeout": "10"}, loadedConfig.Database.Options)
+	assert.True(t, loadedConfig.Features.Signup)
+	assert.Equal(t, "smtp.example.com", loadedConfig.Nested.Mail.Host)
+
+	err = LoadConfigFromDirectory("testdata/missing", "production", &loadedConfig)
+	assert.NotNil(t, err)
+
+	provenance := make(global.Provenance)
+	err = LoadConfigFromDirectoryWithOptions(
+		"testdata/config",
+		"production",
+		LoadConfigOptions{Provenance: provenance},
+		&loadedConfig,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, "testdata/config/database.yml:2", provenance["Database.PoolSize"].Name)
+}
+
+func TestLoadConfigFromDirectoryReplacesLists(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	contents := "default:\n  urls: [a, b]\nproduction:\n  urls: [x]\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "database.yml"), []byte(contents), 0o600))
+
+	var loadedConfig struct {
+		Database struct {
+			URLs []string `json:"urls"`
+		} `json:"database"`
+	}
+	err := LoadConfigFromDirectory(dir, "production", &loadedConfig)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"x"}, loadedConfig.Database.URLs)
+}
@@ -0,0 +1,64 @@
+package yaml
+
+import (
+	"github.com/railsware/go-global/v2"
+	"github.com/railsware/go-global/v2/tree"
+	"github.com/railsware/go-global/v2/utils"
+)
+
+type LoadConfigOptions struct {
+	// Provenance, if not nil, collects the origins of the loaded values, see tree.WriteOptions.
+	Provenance global.Provenance
+}
+
+// LoadConfigFromDirectory reads Global's YAML files from dir and writes them to config.
+//   - config must be a pointer to a struct.
+//   - Every file is a top-level key named after the file, e.g. database.yml becomes "database".
+//     Subdirectories become nested keys.
+//   - Within a file, the "default" section is deep-merged with the section of the given environment.
+//   - Keys are matched to struct fields by: name, `global:` tag, or `json:` tag
+func LoadConfigFromDirectory(dir string, environment string, globalConfig interface{}) global.Error {
+	return LoadConfigFromDirectoryWithOptions(dir, environment, LoadConfigOptions{}, globalConfig)
+}
+
+// LoadConfigFromDirectoryWithOptions is LoadConfigFromDirectory with options.
+func LoadConfigFromDirectoryWithOptions( //nolint:nonamedreturns // using named return for defer
+	dir string,
+	environment string,
+	options LoadConfigOptions,
+	globalConfig interface{},
+) (err global.Error) {
+	defer func() {
+		if panicErr := recover(); panicErr != nil {
+			err = global.NewError("global: panic while loading from YAML files: %v", panicErr)
+			return
+		}
+	}()
+
+	if _, err := utils.ReflectConfig(globalConfig); err != nil {
+		return err
+	}
+
+	paramTree, err := loadDirectoryTree(dir, environment)
+	if err != nil {
+		return err
+	}
+
+	return paramTree.WriteConfig(globalConfig, tree.WriteOptions{Provenance: options.Provenance})
+}
+
+// DirectorySource returns a source of the parameter tree read from YAML files in dir,
+// to be combined with other sources.
+func DirectorySource(dir string, environment string) tree.Source {
+	return func() (*tree.Node, global.Error) {
+		return loadDirectoryTree(dir, environment)
+	}
+}
+
+func loadDirectoryTree(dir string, environment string) (*tree.Node, global.Error) {
+	paramTree, err := buildDirectoryTree(dir, environment)
+	if err != nil {
+		return nil, global.NewError("global: failed to load YAML files: %v", err)
+	}
+	return paramTree, nil
+}